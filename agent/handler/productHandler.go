@@ -5,13 +5,11 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
-	"io"
 	"mime/multipart"
 	"net/http"
 	"nistagram/agent/dto"
 	"nistagram/agent/service"
 	"nistagram/agent/util"
-	"os"
 	"strings"
 )
 
@@ -55,7 +53,7 @@ func (handler *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	err = handler.savePicture(fileName, picture)
+	err = handler.savePicture(fileName, picture, picHeader)
 	if err != nil{
 		fmt.Println(err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -67,22 +65,11 @@ func (handler *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Requ
 	w.Header().Set("Content-Type", "application/json")
 }
 
-func (handler *ProductHandler) savePicture(fileName string, picture multipart.File) error {
-	f, err := os.OpenFile("../../agentstaticdata/data/"+fileName, os.O_WRONLY|os.O_CREATE, 0666)
-	defer func(f *os.File) {
-		_ = f.Close()
-	}(f)
+func (handler *ProductHandler) savePicture(fileName string, picture multipart.File, picHeader *multipart.FileHeader) error {
 	defer func(picture multipart.File) {
 		_ = picture.Close()
 	}(picture)
-	if err != nil {
-		return err
-	}
-	_, err = io.Copy(f, picture)
-	if err != nil {
-		return err
-	}
-	return nil
+	return handler.ProductService.SavePicture(fileName, picture, picHeader.Size, picHeader.Header.Get("Content-Type"))
 }
 
 func (handler *ProductHandler) GetAllProducts(w http.ResponseWriter, r *http.Request){
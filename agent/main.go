@@ -9,6 +9,7 @@ import (
 	"nistagram/agent/repository"
 	"nistagram/agent/service"
 	"nistagram/agent/util"
+	"nistagram/util/mediastore"
 	"os"
 	"time"
 
@@ -115,7 +116,8 @@ func initProductRepo(db *gorm.DB) *repository.ProductRepository {
 }
 
 func initProductService(repo *repository.ProductRepository) *service.ProductService {
-	return &service.ProductService{ProductRepository: repo}
+	return &service.ProductService{ProductRepository: repo,
+		MediaStore: mediastore.NewFromEnv("../../agentstaticdata/data", "/data")}
 }
 
 func initProductHandler(service *service.ProductService) *handler.ProductHandler {
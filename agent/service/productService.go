@@ -1,16 +1,24 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"io"
 	"nistagram/agent/dto"
 	"nistagram/agent/model"
 	"nistagram/agent/repository"
 	"nistagram/agent/util"
+	"nistagram/util/mediastore"
 	"time"
 )
 
 type ProductService struct {
 	ProductRepository *repository.ProductRepository
+	MediaStore        mediastore.MediaStore
+}
+
+func (service *ProductService) SavePicture(fileName string, picture io.Reader, size int64, contentType string) error {
+	return service.MediaStore.Put(context.TODO(), fileName, picture, size, contentType)
 }
 
 func (service *ProductService) CreateProduct(dto dto.ProductDTO, loggedUserId uint, fileName string) error{
@@ -0,0 +1,5 @@
+package dto
+
+type WarningDTO struct {
+	Reason string `json:"reason"`
+}
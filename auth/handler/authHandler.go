@@ -307,6 +307,31 @@ func (handler *AuthHandler) BanUser(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 }
 
+func (handler *AuthHandler) WarnUser(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("WarnUser-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	util.Tracer.LogFields(span, "handler", fmt.Sprintf("handling %s\n", r.URL.Path))
+	vars := mux.Vars(r)
+	var warningDTO dto.WarningDTO
+	if err := json.NewDecoder(r.Body).Decode(&warningDTO); err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	err := handler.AuthService.WarnUser(ctx, util.String2Uint(vars["profileID"]), warningDTO.Reason)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		fmt.Println(err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("{\"success\":\"ok\"}"))
+	w.Header().Set("Content-Type", "application/json")
+}
+
 func (handler *AuthHandler) MakeAgent(w http.ResponseWriter, r *http.Request) {
 	span := util.Tracer.StartSpanFromRequest("MakeAgent-handler", r)
 	defer util.Tracer.FinishSpan(span)
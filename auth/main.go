@@ -101,7 +101,9 @@ func handlerFunc(handler *handler.AuthHandler) {
 		util.MSAuth(handler.GetPrivileges,
 			[]string{"auth", "connection", "post", "profile", "postreaction", "campaign", "notification", "monitoring"})).Methods("GET")
 	router.HandleFunc("/ban/{profileID}",
-		util.MSAuth(handler.BanUser, []string{"profile"})).Methods("DELETE")
+		util.MSAuth(handler.BanUser, []string{"profile", "postreaction"})).Methods("DELETE")
+	router.HandleFunc("/warn/{profileID}",
+		util.MSAuth(handler.WarnUser, []string{"postreaction"})).Methods("POST")
 	router.HandleFunc("/make-agent/{profileID}",
 		util.MSAuth(handler.MakeAgent, []string{"profile"})).Methods("PUT")
 	host, port := util.GetAuthHostAndPort()
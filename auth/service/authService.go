@@ -317,6 +317,26 @@ func (service *AuthService) BanUser(ctx context.Context, profileID uint) error {
 	return nil
 }
 
+// WarnUser mails the owner of the profile that moderators acted on something they posted, with the reason given.
+func (service *AuthService) WarnUser(ctx context.Context, profileID uint, reason string) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "WarnUser-service")
+	defer util.Tracer.FinishSpan(span)
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	user, err := service.AuthRepository.GetUserByProfileID(nextCtx, profileID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	message := "Your content on Nistagram has been reported and reviewed by our moderators. " +
+		"Repeated violations may lead to your account being deleted."
+	if reason != "" {
+		message += "\n\nModerator note: " + reason
+	}
+	go util.SendMail(user.Email, "Community guidelines warning", message)
+	util.Tracer.LogFields(span, "service", fmt.Sprintf("send warning mail to %s", user.Email))
+	return nil
+}
+
 func (service *AuthService) MakeAgent(ctx context.Context, profileID uint) error {
 	span := util.Tracer.StartSpanFromContext(ctx, "MakeAgent-service")
 	defer util.Tracer.FinishSpan(span)
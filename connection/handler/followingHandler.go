@@ -201,6 +201,19 @@ func (handler *Handler) GetFollowerProfiles(w http.ResponseWriter, r *http.Reque
 	json.NewEncoder(w).Encode(*profiles)
 }
 
+func (handler *Handler) GetFollowerIDsNotMuted(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("GetFollowerIDsNotMuted-handler", r)
+	defer util.Tracer.FinishSpan(span)
+	util.Tracer.LogFields(span, "handler", fmt.Sprintf("handling %s\n", r.URL.Path))
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	vars := mux.Vars(r)
+	id, _ := strconv.ParseUint(vars["id"], 10, 32)
+	profiles := handler.ConnectionService.GetFollowerIDsNotMuted(ctx, uint(id))
+	w.WriteHeader(http.StatusOK)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(profiles)
+}
+
 func (handler *Handler) GetMyFollowerProfiles(w http.ResponseWriter, r *http.Request) {
 	span := util.Tracer.StartSpanFromRequest("GetMyFollowerProfiles-handler", r)
 	defer util.Tracer.FinishSpan(span)
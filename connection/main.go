@@ -92,6 +92,9 @@ func handleFunc(handler *handler.Handler) {
 	router.HandleFunc("/connection/following/show/{id}",
 		util.MSAuth(handler.GetFollowedProfilesNotMuted, []string{"post", "profile", "postreaction"})).Methods("GET")
 
+	router.HandleFunc("/connection/followers/show/{id}",
+		util.MSAuth(handler.GetFollowerIDsNotMuted, []string{"post"})).Methods("GET")
+
 	router.HandleFunc("/connection/following/properties/{followerId}/{profileId}", handler.GetConnection).Methods("GET")
 
 	router.HandleFunc("/connection/following/update", handler.UpdateConnection).Methods("PUT") //frontend func
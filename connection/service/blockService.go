@@ -53,6 +53,8 @@ func (service *Service) ToggleBlock(ctx context.Context, followerId, profileId u
 		service.ConnectionRepository.DeleteMessage(nextCtx, followerId, profileId)
 		service.ConnectionRepository.DeleteMessage(nextCtx, profileId, followerId)
 		block, ok = service.ConnectionRepository.CreateBlock(nextCtx, followerId, profileId)
+		unfollowTimeline(nextCtx, followerId, profileId)
+		unfollowTimeline(nextCtx, profileId, followerId)
 	} else {
 		block, ok = service.ConnectionRepository.DeleteBlock(nextCtx, followerId, profileId)
 	}
@@ -58,6 +58,20 @@ func (service *Service) GetConnectedProfiles(ctx context.Context, conn model.Con
 	return &ret
 }
 
+// GetFollowerIDsNotMuted lists the followers of the profile that did not mute it, the ones its posts are pushed to.
+func (service *Service) GetFollowerIDsNotMuted(ctx context.Context, id uint) []uint {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetFollowerIDsNotMuted-service")
+	defer util.Tracer.FinishSpan(span)
+	util.Tracer.LogFields(span, "service", fmt.Sprintf("servicing id %v\n", id))
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	conn := model.ConnectionEdge{SecondaryProfile: id, Approved: true}
+	profiles := service.ConnectionRepository.GetConnectedProfiles(nextCtx, conn, true, false)
+	if profiles == nil {
+		return make([]uint, 0)
+	}
+	return *profiles
+}
+
 func (service *Service) GetProfilesInFollowRelationship(ctx context.Context,conn model.ConnectionEdge, excludeMuted, excludeBlocked bool, following bool) *[]dto.UserDTO {
 	span := util.Tracer.StartSpanFromContext(ctx, "GetProfilesInFollowRelationship-service")
 	defer util.Tracer.FinishSpan(span)
@@ -151,6 +165,9 @@ func (service *Service) FollowRequest(ctx context.Context, followerId, profileId
 	})
 	resConnection, ok := service.ConnectionRepository.UpdateConnection(nextCtx, connection)
 	if ok {
+		if resConnection.Approved {
+			followTimeline(nextCtx, followerId, profileId)
+		}
 		return resConnection, true
 	} else {
 		return connection, false
@@ -182,7 +199,11 @@ func (service *Service) ApproveConnection(ctx context.Context, followerId, profi
 		Approved:         true,
 		NotifyMessage:    true,
 	})
-	return service.ConnectionRepository.UpdateConnection(nextCtx, connection)
+	resConnection, ok := service.ConnectionRepository.UpdateConnection(nextCtx, connection)
+	if ok {
+		followTimeline(nextCtx, followerId, profileId)
+	}
+	return resConnection, ok
 }
 
 func (service *Service) Unfollow(ctx context.Context, followerId, profileId uint) (*model.ConnectionEdge, bool) {
@@ -199,6 +220,7 @@ func (service *Service) Unfollow(ctx context.Context, followerId, profileId uint
 	}
 	resConnection, ok := service.ConnectionRepository.DeleteConnection(nextCtx, followerId, profileId)
 	if ok {
+		unfollowTimeline(nextCtx, followerId, profileId)
 		return resConnection, true
 	} else {
 		return connection, false
@@ -343,6 +365,11 @@ func (service *Service) ToggleMuted(ctx context.Context, followerId, profileId u
 	connection.Muted = !connection.Muted
 	resConnection, ok := service.ConnectionRepository.UpdateConnection(nextCtx, connection)
 	if ok {
+		if resConnection.Muted {
+			unfollowTimeline(nextCtx, followerId, profileId)
+		} else if resConnection.Approved {
+			followTimeline(nextCtx, followerId, profileId)
+		}
 		return resConnection, true
 	} else {
 		return connection, false
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"nistagram/util"
+)
+
+// followTimeline and unfollowTimeline keep the cached home feed of the follower in the post service in step with the
+// connection; the feed is a cache, so a failed update is only logged.
+func followTimeline(ctx context.Context, followerId, profileId uint) {
+	updateTimeline(ctx, http.MethodPut, followerId, profileId)
+}
+
+func unfollowTimeline(ctx context.Context, followerId, profileId uint) {
+	updateTimeline(ctx, http.MethodDelete, followerId, profileId)
+}
+
+func updateTimeline(ctx context.Context, method string, followerId, profileId uint) {
+	span := util.Tracer.StartSpanFromContext(ctx, "updateTimeline-service")
+	defer util.Tracer.FinishSpan(span)
+	util.Tracer.LogFields(span, "service", fmt.Sprintf("servicing id %v %v\n", followerId, profileId))
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	postHost, postPort := util.GetPostHostAndPort()
+	resp, err := util.CrossServiceRequest(nextCtx, method,
+		util.GetCrossServiceProtocol()+"://"+postHost+":"+postPort+"/timeline/"+util.Uint2String(followerId)+"/following/"+util.Uint2String(profileId),
+		nil, map[string]string{})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		fmt.Println(err)
+		return
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		util.Tracer.LogError(span, fmt.Errorf("timeline update failed with status %d", resp.StatusCode))
+	}
+}
@@ -0,0 +1,23 @@
+import axios from 'axios'
+import * as comm from './communication.js'
+
+let options = null
+
+// getReactionOptions loads the reactions offered on posts once and shares them between components.
+export function getReactionOptions() {
+  if (options == null) {
+    options = axios({
+      method: 'get',
+      url: comm.protocol + '://' + comm.server + '/api/postreaction/reaction-options',
+    }).then(response => response.data).catch(() => {
+      options = null;
+      return [];
+    });
+  }
+  return options;
+}
+
+// extraReactions are the offered reactions other than like and dislike, which have their own buttons.
+export function extraReactions(reactionOptions) {
+  return reactionOptions.filter(option => option.type != 'like' && option.type != 'dislike');
+}
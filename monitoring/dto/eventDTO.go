@@ -7,4 +7,5 @@ type EventDTO struct {
 	CampaignId		uint		`json:"campaignId"`
 	InfluencerId	uint		`json:"influencerId"`
 	WebSite 		string		`json:"webSite"`
+	Reaction		string		`json:"reaction"`
 }
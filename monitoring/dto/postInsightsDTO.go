@@ -0,0 +1,22 @@
+package dto
+
+// PostEventsDTO reports that the profile saw the posts, or visited their publisher from them; anonymous viewers have
+// the ID 0.
+type PostEventsDTO struct {
+	EventType string   `json:"eventType"`
+	ProfileId uint     `json:"profileId"`
+	PostIds   []string `json:"postIds"`
+}
+
+// PostInsightsDTO holds the daily post events of a post; Reach counts the distinct logged viewers of the whole period.
+type PostInsightsDTO struct {
+	Reach int                    `json:"reach"`
+	Days  []DailyPostInsightsDTO `json:"days"`
+}
+
+type DailyPostInsightsDTO struct {
+	Day           string `json:"day"`
+	Impressions   int    `json:"impressions"`
+	Reach         int    `json:"reach"`
+	ProfileVisits int    `json:"profileVisits"`
+}
@@ -8,6 +8,7 @@ type StatisticsDTO struct {
 	CampaignId		uint				  `json:"campaignId"`
 	Campaign 		CampaignMonitoringDTO `json:"campaign"`
 	Events 	 		[]ShowEventDTO		  `json:"events"`
+	Reactions		map[string]int		  `json:"reactions"`
 }
 
 type CampaignMonitoringDTO struct {
@@ -39,4 +40,5 @@ type ShowEventDTO struct {
 	Interests			    []string	`json:"interests"`
 	WebSite 				string		`json:"webSite"`
 	Timestamp   		    time.Time 	`json:"timestamp"`
+	Reaction				string		`json:"reaction"`
 }
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"net/http"
+	"nistagram/monitoring/dto"
+	"nistagram/util"
+	"time"
+)
+
+func (handler *Handler) CreatePostEvents(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("CreatePostEvents-handler", r)
+	defer util.Tracer.FinishSpan(span)
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+
+	var eventsDto dto.PostEventsDTO
+	if err := json.NewDecoder(r.Body).Decode(&eventsDto); err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("{\"message\":\"error\"}"))
+		return
+	}
+
+	if err := handler.MonitoringService.CreatePostEvents(ctx, eventsDto); err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("{\"message\":\"error\"}"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("{\"message\":\"ok\"}"))
+}
+
+func (handler *Handler) GetPostInsights(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("GetPostInsights-handler", r)
+	defer util.Tracer.FinishSpan(span)
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+	vars := mux.Vars(r)
+
+	from, err := time.Parse(time.RFC3339, r.URL.Query().Get("from"))
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	to, err := time.Parse(time.RFC3339, r.URL.Query().Get("to"))
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	result, err := handler.MonitoringService.GetPostInsights(ctx, vars["postId"], from, to)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("{\"message\":\"error\"}"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(result)
+}
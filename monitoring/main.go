@@ -68,6 +68,10 @@ func handleFunc(handler *handler.Handler) {
 	router.HandleFunc("/target-group",
 		util.MSAuth(handler.CreateEventTargetGroup, []string{"postreaction"})).Methods("POST")
 	router.HandleFunc("/statistics/{campaignId}", util.AgentAuth(handler.GetCampaignStatistics)).Methods("GET")
+	router.HandleFunc("/post-events",
+		util.MSAuth(handler.CreatePostEvents, []string{"post"})).Methods("POST")
+	router.HandleFunc("/post-insights/{postId}",
+		util.MSAuth(handler.GetPostInsights, []string{"post"})).Methods("GET")
 	router.HandleFunc("/redirect/{campaignId}/{influencerId}/{mediaId}", handler.VisitSite).Methods("GET")
 
 	fmt.Println("Starting server..")
@@ -12,6 +12,27 @@ type Event struct {
 	CampaignId        	  uint      	`json:"campaignId"`
 	Interests			  []string		`json:"interests"`
 	WebSite 			  string		`json:"webSite"`
+	ProfileId			  uint			`json:"profileId"`
+	Reaction			  string		`json:"reaction"`
+}
+
+// ReactionName is the reaction a reaction event sets or resets; events stored before reactions were named only
+// have their type to tell likes from dislikes.
+func (event *Event) ReactionName() string {
+	switch event.EventType {
+	case LIKE, LIKE_RESET:
+		return "like"
+	case DISLIKE, DISLIKE_RESET:
+		return "dislike"
+	case REACTION, REACTION_RESET:
+		return event.Reaction
+	}
+	return ""
+}
+
+// IsReset tells whether the event takes back a reaction.
+func (event *Event) IsReset() bool {
+	return event.EventType == LIKE_RESET || event.EventType == DISLIKE_RESET || event.EventType == REACTION_RESET
 }
 
 func (event *Event) AddInterest(i string) {
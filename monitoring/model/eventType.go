@@ -10,6 +10,13 @@ const (
 	LIKE_RESET
 	DISLIKE_RESET
 	NONE
+	// Post events are counted for the insights of ordinary posts; they come after NONE so that the types of the
+	// campaign events stored before them do not change.
+	IMPRESSION
+	PROFILE_VISIT
+	// Reactions other than likes and dislikes, named by the reaction of the event.
+	REACTION
+	REACTION_RESET
 )
 
 type EventType int
@@ -33,6 +40,18 @@ func GetEventType(eventType string) EventType {
 	if strings.ToLower(eventType) == "dislike_reset" {
 		return DISLIKE_RESET
 	}
+	if strings.ToLower(eventType) == "impression" {
+		return IMPRESSION
+	}
+	if strings.ToLower(eventType) == "profile_visit" {
+		return PROFILE_VISIT
+	}
+	if strings.ToLower(eventType) == "reaction" {
+		return REACTION
+	}
+	if strings.ToLower(eventType) == "reaction_reset" {
+		return REACTION_RESET
+	}
 	return NONE
 }
 
@@ -50,6 +69,14 @@ func (e EventType) ToString() string {
 		return "LIKE_RESET"
 	case DISLIKE_RESET:
 		return "DISLIKE_RESET"
+	case IMPRESSION:
+		return "IMPRESSION"
+	case PROFILE_VISIT:
+		return "PROFILE_VISIT"
+	case REACTION:
+		return "REACTION"
+	case REACTION_RESET:
+		return "REACTION_RESET"
 	default:
 		return "NONE"
 	}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nistagram/monitoring/model"
+	"nistagram/util"
+	"time"
+)
+
+// InsightsDayFormat names the UTC day the post events are grouped by.
+const InsightsDayFormat = "%Y-%m-%d"
+
+// DailyPostEvents counts the events of one type a post got in a day, with the logged viewers behind them.
+type DailyPostEvents struct {
+	Day       string
+	EventType model.EventType
+	Count     int
+	Viewers   []uint
+}
+
+func (repo *MonitoringRepository) CreateMany(ctx context.Context, events []model.Event) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "CreateMany-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	if len(events) == 0 {
+		return nil
+	}
+	documents := make([]interface{}, 0)
+	for _, event := range events {
+		documents = append(documents, event)
+	}
+	_, err := repo.getCollection().InsertMany(context.TODO(), documents)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+	}
+	return err
+}
+
+// GetDailyPostEvents groups the impressions and profile visits of the post recorded in [from, to) by day and type.
+func (repo *MonitoringRepository) GetDailyPostEvents(ctx context.Context, postId string, from time.Time, to time.Time) ([]DailyPostEvents, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetDailyPostEvents-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	pipeline := mongo.Pipeline{
+		{{"$match", postEventsFilter(postId, bson.A{model.IMPRESSION, model.PROFILE_VISIT}, from, to)}},
+		{{"$group", bson.D{
+			{"_id", bson.D{
+				{"day", bson.D{{"$dateToString", bson.D{{"format", InsightsDayFormat}, {"date", "$timestamp"}}}}},
+				{"eventtype", "$eventtype"},
+			}},
+			{"count", bson.D{{"$sum", 1}}},
+			{"viewers", bson.D{{"$addToSet", "$profileid"}}},
+		}}},
+	}
+	cursor, err := repo.getCollection().Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	var results []struct {
+		ID struct {
+			Day       string          `bson:"day"`
+			EventType model.EventType `bson:"eventtype"`
+		} `bson:"_id"`
+		Count   int    `bson:"count"`
+		Viewers []uint `bson:"viewers"`
+	}
+	if err = cursor.All(context.TODO(), &results); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	ret := make([]DailyPostEvents, 0)
+	for _, result := range results {
+		ret = append(ret, DailyPostEvents{Day: result.ID.Day, EventType: result.ID.EventType, Count: result.Count,
+			Viewers: result.Viewers})
+	}
+	return ret, nil
+}
+
+// CountPostReach counts the distinct logged viewers the post was shown to in [from, to).
+func (repo *MonitoringRepository) CountPostReach(ctx context.Context, postId string, from time.Time, to time.Time) (int, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "CountPostReach-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	filter := postEventsFilter(postId, bson.A{model.IMPRESSION}, from, to)
+	filter = append(filter, bson.E{"profileid", bson.D{{"$ne", 0}}})
+	viewers, err := repo.getCollection().Distinct(context.TODO(), "profileid", filter)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return 0, err
+	}
+	return len(viewers), nil
+}
+
+// HasProfileEvent tells whether the profile already has an event of the type on the post in [from, to).
+func (repo *MonitoringRepository) HasProfileEvent(ctx context.Context, postId string, eventType model.EventType, profileId uint, from time.Time, to time.Time) (bool, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "HasProfileEvent-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	filter := postEventsFilter(postId, bson.A{eventType}, from, to)
+	filter = append(filter, bson.E{"profileid", profileId})
+	count, err := repo.getCollection().CountDocuments(context.TODO(), filter, options.Count().SetLimit(1))
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func postEventsFilter(postId string, eventTypes bson.A, from time.Time, to time.Time) bson.D {
+	return bson.D{
+		{"originalpostid", postId},
+		{"campaignid", 0},
+		{"eventtype", bson.D{{"$in", eventTypes}}},
+		{"timestamp", bson.D{{"$gte", from}, {"$lt", to}}},
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"nistagram/monitoring/dto"
+	"nistagram/monitoring/model"
+	"nistagram/util"
+	"time"
+)
+
+// CreatePostEvents records an impression or a profile visit for each of the posts. Profile visits are only recorded
+// for logged profiles, once per post and UTC day.
+func (service *MonitoringService) CreatePostEvents(ctx context.Context, eventsDto dto.PostEventsDTO) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "CreatePostEvents-service")
+	defer util.Tracer.FinishSpan(span)
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	eventType := model.GetEventType(eventsDto.EventType)
+	if eventType != model.IMPRESSION && eventType != model.PROFILE_VISIT {
+		util.Tracer.LogError(span, fmt.Errorf("invalid post event type %s", eventsDto.EventType))
+		return fmt.Errorf("INVALID_EVENT_TYPE")
+	}
+	if eventType == model.PROFILE_VISIT && eventsDto.ProfileId == 0 {
+		return nil
+	}
+	now := time.Now()
+	day := now.UTC().Truncate(24 * time.Hour)
+	events := make([]model.Event, 0)
+	for _, postId := range eventsDto.PostIds {
+		if eventType == model.PROFILE_VISIT {
+			visited, err := service.MonitoringRepository.HasProfileEvent(nextCtx, postId, eventType, eventsDto.ProfileId,
+				day, day.Add(24*time.Hour))
+			if err != nil {
+				util.Tracer.LogError(span, err)
+				return err
+			}
+			if visited {
+				continue
+			}
+		}
+		events = append(events, model.Event{OriginalPostId: postId, EventType: eventType,
+			ProfileId: eventsDto.ProfileId, Timestamp: now})
+	}
+	return service.MonitoringRepository.CreateMany(nextCtx, events)
+}
+
+// GetPostInsights counts the impressions, the reach and the profile visits of the post by day, for the days of
+// [from, to) the post got any of them.
+func (service *MonitoringService) GetPostInsights(ctx context.Context, postId string, from time.Time, to time.Time) (dto.PostInsightsDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetPostInsights-service")
+	defer util.Tracer.FinishSpan(span)
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	events, err := service.MonitoringRepository.GetDailyPostEvents(nextCtx, postId, from, to)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostInsightsDTO{}, err
+	}
+	reach, err := service.MonitoringRepository.CountPostReach(nextCtx, postId, from, to)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostInsightsDTO{}, err
+	}
+
+	days := make(map[string]*dto.DailyPostInsightsDTO)
+	ret := dto.PostInsightsDTO{Reach: reach, Days: make([]dto.DailyPostInsightsDTO, 0)}
+	for _, event := range events {
+		day, ok := days[event.Day]
+		if !ok {
+			day = &dto.DailyPostInsightsDTO{Day: event.Day}
+			days[event.Day] = day
+		}
+		switch event.EventType {
+		case model.IMPRESSION:
+			day.Impressions = event.Count
+			for _, viewer := range event.Viewers {
+				if viewer != 0 {
+					day.Reach++
+				}
+			}
+		case model.PROFILE_VISIT:
+			day.ProfileVisits = event.Count
+		}
+	}
+	for _, day := range days {
+		ret.Days = append(ret.Days, *day)
+	}
+	return ret, nil
+}
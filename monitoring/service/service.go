@@ -10,6 +10,7 @@ import (
 	"nistagram/monitoring/model"
 	"nistagram/monitoring/repository"
 	"nistagram/util"
+	"strings"
 	"time"
 )
 
@@ -26,7 +27,8 @@ func (service *MonitoringService) CreateEventInfluencer(ctx context.Context, eve
 	event := &model.Event{OriginalPostId: eventDto.PostId,
 		EventType: model.GetEventType(eventDto.EventType), WebSite: eventDto.WebSite,
 		InfluencerId: eventDto.InfluencerId,
-		Timestamp:    time.Now(), CampaignId: eventDto.CampaignId, Interests: interests}
+		Timestamp:    time.Now(), CampaignId: eventDto.CampaignId, Interests: interests,
+		Reaction:     strings.ToLower(eventDto.Reaction)}
 
 	err := service.MonitoringRepository.Create(nextCtx, event)
 	return err
@@ -60,7 +62,8 @@ func (service *MonitoringService) CreateEventTargetGroup(ctx context.Context, ev
 
 	event := &model.Event{OriginalPostId: eventDto.PostId,
 		EventType: model.GetEventType(eventDto.EventType), WebSite: eventDto.WebSite, InfluencerId: eventDto.InfluencerId,
-		Timestamp: time.Now(), CampaignId: eventDto.CampaignId, Interests: interests}
+		Timestamp: time.Now(), CampaignId: eventDto.CampaignId, Interests: interests,
+		Reaction: strings.ToLower(eventDto.Reaction)}
 
 	err := service.MonitoringRepository.Create(nextCtx, event)
 	return err
@@ -144,16 +147,25 @@ func (service *MonitoringService) GetCampaignStatistics(ctx context.Context, cam
 	}
 
 	var eventDtos []dto.ShowEventDTO
+	reactions := make(map[string]int)
 	for _, e := range events {
 		eventDto := &dto.ShowEventDTO{EventType: e.EventType.ToString(), InfluencerId: e.InfluencerId,
 			InfluencerUsername: usersMap[e.InfluencerId],
-			Interests:          e.Interests, WebSite: e.WebSite, Timestamp: e.Timestamp}
+			Interests:          e.Interests, WebSite: e.WebSite, Timestamp: e.Timestamp, Reaction: e.ReactionName()}
+		if reaction := e.ReactionName(); reaction != "" {
+			if e.IsReset() {
+				reactions[reaction]--
+			} else {
+				reactions[reaction]++
+			}
+		}
 		eventDtos = append(eventDtos, *eventDto)
 	}
 
 	statistics.CampaignId = campaignId
 	statistics.Campaign = campaign
 	statistics.Events = eventDtos
+	statistics.Reactions = reactions
 	return statistics, err
 }
 
@@ -224,8 +236,16 @@ func getMediaByIdFromPostMs(ctx context.Context, mediaId string) (string, error)
 		util.Tracer.LogError(span, err)
 		return "", err
 	}
+	if resp.StatusCode != http.StatusOK {
+		util.Tracer.LogError(span, fmt.Errorf("media %s request failed with status %d", mediaId, resp.StatusCode))
+		return "", fmt.Errorf("MEDIA_NOT_FOUND")
+	}
 
 	err = json.NewDecoder(resp.Body).Decode(&dto)
+	if err == nil && dto.WebSite == "" {
+		util.Tracer.LogError(span, fmt.Errorf("media %s has no website", mediaId))
+		return "", fmt.Errorf("MEDIA_NOT_FOUND")
+	}
 
 	return dto.WebSite, err
 }
@@ -6,12 +6,11 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
-	"io"
 	"mime/multipart"
 	"net/http"
 	"nistagram/notification/model"
+	"nistagram/notification/service"
 	"nistagram/util"
-	"os"
 	"strings"
 )
 
@@ -91,6 +90,34 @@ func (handler *Handler) GetAllMesssages(w http.ResponseWriter, r *http.Request)
 	w.Header().Set("Content-Type", "application/json")
 }
 
+func (handler *Handler) GetMessageForParticipant(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("GetMessageForParticipant-handler", r)
+	defer util.Tracer.FinishSpan(span)
+	util.Tracer.LogFields(span, "handler", fmt.Sprintf("handling %s\n", r.URL.Path))
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	vars := mux.Vars(r)
+
+	message, err := handler.Service.GetMessageForParticipant(ctx, util.String2Uint(vars["profileId"]), vars["id"])
+	switch err {
+	case nil:
+	case service.ErrMessageNotFound:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	case service.ErrNotParticipant:
+		w.WriteHeader(http.StatusForbidden)
+		return
+	default:
+		util.Tracer.LogError(span, err)
+		fmt.Println(err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(message)
+}
+
 func (handler *Handler) SaveFile(w http.ResponseWriter, r *http.Request) {
 	span := util.Tracer.StartSpanFromRequest("SaveFile-handler", r)
 	defer util.Tracer.FinishSpan(span)
@@ -116,20 +143,11 @@ func (handler *Handler) SaveFile(w http.ResponseWriter, r *http.Request) {
 
 	fileSplitted := strings.Split(fileHeader.Filename, ".")
 	fileName := uid + "." + fileSplitted[1]
-	f, err := os.OpenFile("../../nistagramstaticdata/data/"+fileName, os.O_WRONLY|os.O_CREATE, 0666)
-	defer func(f *os.File) {
-		_ = f.Close()
-	}(f)
 	defer func(picture multipart.File) {
 		_ = picture.Close()
 	}(file)
-	if err != nil {
-		fmt.Println(err)
-		w.WriteHeader(http.StatusInternalServerError)
-		_, _ = w.Write([]byte("{\"message\":\"error\"}"))
-		return
-	}
-	_, err = io.Copy(f, file)
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	err = handler.Service.SaveFile(ctx, fileName, file, fileHeader.Size, fileHeader.Header.Get("Content-Type"))
 	if err != nil {
 		fmt.Println(err)
 		w.WriteHeader(http.StatusInternalServerError)
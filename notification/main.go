@@ -8,6 +8,7 @@ import (
 	"nistagram/notification/repository"
 	"nistagram/notification/service"
 	"nistagram/util"
+	"nistagram/util/mediastore"
 	"os"
 	"time"
 
@@ -52,7 +53,8 @@ func initRepo(client *mongo.Client) *repository.Repository {
 	return &repository.Repository{Client: client}
 }
 func initService(repository *repository.Repository) *service.Service {
-	return &service.Service{Repository: repository}
+	return &service.Service{Repository: repository,
+		MediaStore: mediastore.NewFromEnv("../../nistagramstaticdata/data", "/static/data")}
 }
 func initHandler(service *service.Service) *handler.Handler {
 	return &handler.Handler{Service: service}
@@ -70,6 +72,9 @@ func handleFunc(handler *handler.Handler) {
 
 	router.HandleFunc("/message/{id}", util.RBAC(handler.GetAllMesssages, "MESSAGING", true)).Methods("GET")
 
+	router.HandleFunc("/message/{id}/participant/{profileId}",
+		util.MSAuth(handler.GetMessageForParticipant, []string{"postreaction"})).Methods("GET")
+
 	router.HandleFunc("/file", util.RBAC(handler.SaveFile, "MESSAGING", false)).Methods("POST")
 
 	router.HandleFunc("/seen/{id}", util.RBAC(handler.Seen, "MESSAGING", false)).Methods("PUT")
@@ -0,0 +1,6 @@
+package service
+
+import "errors"
+
+var ErrMessageNotFound = errors.New("MESSAGE_NOT_FOUND")
+var ErrNotParticipant = errors.New("NOT_PARTICIPANT")
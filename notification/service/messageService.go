@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"io"
 	"nistagram/notification/model"
 	"nistagram/util"
 	"sort"
@@ -22,6 +23,14 @@ func (service *Service) CreateMessage(ctx context.Context, message *model.Messag
 	return service.Repository.CreateMessage(nextCtx, message)
 }
 
+func (service *Service) SaveFile(ctx context.Context, fileName string, content io.Reader, size int64, contentType string) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "SaveFile-service")
+	defer util.Tracer.FinishSpan(span)
+	util.Tracer.LogFields(span, "service", fmt.Sprintf("servicing file %v\n", fileName))
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	return service.MediaStore.Put(nextCtx, fileName, content, size, contentType)
+}
+
 func (service *Service) Seen(ctx context.Context, messageId string) error{
 	span := util.Tracer.StartSpanFromContext(ctx, "Seen-service")
 	defer util.Tracer.FinishSpan(span)
@@ -76,6 +85,27 @@ func (service *Service) DeleteMessage(ctx context.Context, loggedUserId uint, me
 	return err
 }
 
+// GetMessageForParticipant returns the message only to its sender or receiver, so others can not read or report it.
+func (service *Service) GetMessageForParticipant(ctx context.Context, profileId uint, messageId string) (*model.Message, error){
+	span := util.Tracer.StartSpanFromContext(ctx, "GetMessageForParticipant-service")
+	defer util.Tracer.FinishSpan(span)
+	util.Tracer.LogFields(span, "service", fmt.Sprintf("servicing id %v %v\n", profileId, messageId))
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	message, err := service.Repository.GetMessageById(nextCtx, messageId)
+	if err != nil{
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	if message.ID.IsZero(){
+		return nil, ErrMessageNotFound
+	}
+	if message.SenderId != profileId && message.ReceiverId != profileId{
+		util.Tracer.LogError(span, fmt.Errorf("profile %d is not a participant of message %s", profileId, messageId))
+		return nil, ErrNotParticipant
+	}
+	return &message, nil
+}
+
 func (service *Service) GetNotifications(ctx context.Context, receiverId uint) ([]string,error){
 	span := util.Tracer.StartSpanFromContext(ctx, "GetNotifications-service")
 	defer util.Tracer.FinishSpan(span)
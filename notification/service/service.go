@@ -1,7 +1,11 @@
 package service
 
-import "nistagram/notification/repository"
+import (
+	"nistagram/notification/repository"
+	"nistagram/util/mediastore"
+)
 
 type Service struct {
 	Repository *repository.Repository
+	MediaStore mediastore.MediaStore
 }
\ No newline at end of file
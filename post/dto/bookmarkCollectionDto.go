@@ -0,0 +1,10 @@
+package dto
+
+type BookmarkCollectionDto struct {
+	Name string `json:"name"`
+}
+
+type SavePostDto struct {
+	PostID       string `json:"postId"`
+	CollectionID string `json:"collectionId"`
+}
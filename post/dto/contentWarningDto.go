@@ -0,0 +1,8 @@
+package dto
+
+// ContentWarningDTO comes with sensitive posts; Blur asks the client to hide the media behind an interstitial until
+// the viewer chooses to see it.
+type ContentWarningDTO struct {
+	Category string `json:"category"`
+	Blur     bool   `json:"blur"`
+}
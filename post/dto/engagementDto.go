@@ -0,0 +1,12 @@
+package dto
+
+type EngagementDTO struct {
+	PostID   string `json:"postId"`
+	Likes    int    `json:"likes"`
+	Comments int    `json:"comments"`
+}
+
+type InteractionDTO struct {
+	PostID string `json:"postId"`
+	Count  int    `json:"count"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+type HashTagTrendDTO struct {
+	Name          string  `json:"name"`
+	Count         int     `json:"count"`
+	PreviousCount int     `json:"previousCount"`
+	Growth        float64 `json:"growth"`
+}
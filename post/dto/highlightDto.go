@@ -0,0 +1,14 @@
+package dto
+
+import "nistagram/post/model"
+
+type HighlightDto struct {
+	Title      string   `json:"title"`
+	CoverMedia string   `json:"coverMedia"`
+	StoryIDs   []string `json:"storyIds"`
+}
+
+type ResponseHighlightDTO struct {
+	Highlight model.Highlight `json:"highlight"`
+	Stories   []model.Post    `json:"stories"`
+}
@@ -0,0 +1,56 @@
+package dto
+
+import "time"
+
+// PostInsightsDTO describes how a post did in [From, To); Totals.Reach counts the distinct viewers of the whole
+// period, so it is not the sum of the daily reach.
+type PostInsightsDTO struct {
+	PostID string             `json:"postId"`
+	From   time.Time          `json:"from"`
+	To     time.Time          `json:"to"`
+	Totals InsightCountsDTO   `json:"totals"`
+	Days   []DailyInsightsDTO `json:"days"`
+}
+
+type DailyInsightsDTO struct {
+	Day string `json:"day"`
+	InsightCountsDTO
+}
+
+type InsightCountsDTO struct {
+	Impressions   int `json:"impressions"`
+	Reach         int `json:"reach"`
+	Likes         int `json:"likes"`
+	Dislikes      int `json:"dislikes"`
+	Comments      int `json:"comments"`
+	Saves         int `json:"saves"`
+	ProfileVisits int `json:"profileVisits"`
+}
+
+// PostEventsDTO reports impressions or profile visits of posts to the monitoring service.
+type PostEventsDTO struct {
+	EventType string   `json:"eventType"`
+	ProfileID uint     `json:"profileId"`
+	PostIDs   []string `json:"postIds"`
+}
+
+// MonitoredInsightsDTO holds the daily impressions, reach and profile visits the monitoring service counted.
+type MonitoredInsightsDTO struct {
+	Reach int                   `json:"reach"`
+	Days  []MonitoredInsightDTO `json:"days"`
+}
+
+type MonitoredInsightDTO struct {
+	Day           string `json:"day"`
+	Impressions   int    `json:"impressions"`
+	Reach         int    `json:"reach"`
+	ProfileVisits int    `json:"profileVisits"`
+}
+
+// DailyEngagementDTO holds the daily likes, dislikes and comments the post reaction service counted.
+type DailyEngagementDTO struct {
+	Day      string `json:"day"`
+	Likes    int    `json:"likes"`
+	Dislikes int    `json:"dislikes"`
+	Comments int    `json:"comments"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+// MediaDto describes a slide of a carousel. On upload the n-th entry applies to the n-th file; when the slides of a
+// post are arranged, ID picks the slide and the order of the entries is the new order of the slides.
+type MediaDto struct {
+	ID      string `json:"id"`
+	AltText string `json:"altText"`
+	WebSite string `json:"webSite"`
+}
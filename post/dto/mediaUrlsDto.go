@@ -0,0 +1,7 @@
+package dto
+
+type MediaURLsDTO struct {
+	MediaID    string            `json:"mediaId"`
+	Original   string            `json:"original"`
+	Thumbnails map[string]string `json:"thumbnails"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+import "nistagram/post/model"
+
+type PlaceDTO struct {
+	Name      string         `json:"name"`
+	Point     model.GeoPoint `json:"point"`
+	PostCount int            `json:"postCount"`
+}
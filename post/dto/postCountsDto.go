@@ -0,0 +1,14 @@
+package dto
+
+// CountsDTO counts the reactions of each type and the comments of a post.
+type CountsDTO struct {
+	Reactions map[string]int `json:"reactions"`
+	Comments  int            `json:"comments"`
+}
+
+// PostCountsDTO is what the postreaction service counts for a post, with the reaction the viewer left on it.
+type PostCountsDTO struct {
+	PostID   string `json:"postId"`
+	Reaction string `json:"reaction"`
+	CountsDTO
+}
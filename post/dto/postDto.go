@@ -1,11 +1,20 @@
 package dto
 
+import "time"
+
 type PostDto struct {
-	Description        string   `json:"description"`
-	IsHighlighted      bool     `json:"isHighlighted"`
-	IsCloseFriendsOnly bool     `json:"isCloseFriendsOnly"`
-	Location           string   `json:"location"`
-	HashTags           string   `json:"hashTags"`
-	PostType           string   `json:"postType"`
-	Links              []string `json:"links"`
+	Description        string       `json:"description"`
+	IsHighlighted      bool         `json:"isHighlighted"`
+	IsCloseFriendsOnly bool         `json:"isCloseFriendsOnly"`
+	Location           string       `json:"location"`
+	Latitude           *float64     `json:"latitude"`
+	Longitude          *float64     `json:"longitude"`
+	HashTags           string       `json:"hashTags"`
+	PostType           string       `json:"postType"`
+	Links              []string     `json:"links"`
+	Medias             []MediaDto   `json:"medias"`
+	Status             string       `json:"status"`
+	ScheduledAt        *time.Time   `json:"scheduledAt"`
+	UserTags           []UserTagDto `json:"userTags"`
+	SensitiveCategory  string       `json:"sensitiveCategory"`
 }
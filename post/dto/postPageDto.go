@@ -0,0 +1,6 @@
+package dto
+
+type PostPageDTO struct {
+	Posts      []ResponsePostDTO `json:"posts"`
+	NextCursor string            `json:"nextCursor"`
+}
@@ -7,6 +7,7 @@ type ProfileDto struct {
 }
 
 type ProfileSettings struct {
-	IsPrivate    bool `json:"isPrivate"`
-	CanBeTagged  bool `json:"canBeTagged"`
+	IsPrivate            bool `json:"isPrivate"`
+	CanBeTagged          bool `json:"canBeTagged"`
+	ShowSensitiveContent bool `json:"showSensitiveContent"`
 }
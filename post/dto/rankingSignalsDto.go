@@ -0,0 +1,16 @@
+package dto
+
+// RankingSignalsDTO explains the score of a post in the ranked home feed; the score is the product of the recency,
+// engagement, close friend and affinity factors.
+type RankingSignalsDTO struct {
+	Score             float64 `json:"score"`
+	AgeHours          float64 `json:"ageHours"`
+	Recency           float64 `json:"recency"`
+	Likes             int     `json:"likes"`
+	Comments          int     `json:"comments"`
+	Engagement        float64 `json:"engagement"`
+	CloseFriend       bool    `json:"closeFriend"`
+	CloseFriendFactor float64 `json:"closeFriendFactor"`
+	Interactions      int     `json:"interactions"`
+	Affinity          float64 `json:"affinity"`
+}
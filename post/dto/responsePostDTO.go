@@ -5,7 +5,12 @@ import "nistagram/post/model"
 type ResponsePostDTO struct {
 	Post               model.Post       `json:"post"`
 	Reaction 		   string           `json:"reaction"`
+	IsEdited		   bool				`json:"isEdited"`
+	MediaURLs		   []MediaURLsDTO	`json:"mediaUrls"`
 	CampaignId		   uint				`json:"campaignId"`
 	InfluencerId	   uint				`json:"influencerId"`
 	InfluencerUsername string			`json:"influencerUsername"`
+	Ranking            *RankingSignalsDTO `json:"ranking,omitempty"`
+	ContentWarning     *ContentWarningDTO `json:"contentWarning,omitempty"`
+	Counts             *CountsDTO         `json:"counts,omitempty"`
 }
@@ -0,0 +1,14 @@
+package dto
+
+import "time"
+
+type StoryViewersDTO struct {
+	Viewers []StoryViewerDTO `json:"viewers"`
+	Count   int              `json:"count"`
+}
+
+type StoryViewerDTO struct {
+	ProfileID uint      `json:"profileId"`
+	Username  string    `json:"username"`
+	ViewedAt  time.Time `json:"viewedAt"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+// UserTagDto places a tag on the media at MediaIndex, in the order of the slides of the post; without an index the tag
+// applies to the whole post.
+type UserTagDto struct {
+	ProfileID  uint    `json:"profileId"`
+	MediaIndex *int    `json:"mediaIndex"`
+	X          float64 `json:"x"`
+	Y          float64 `json:"y"`
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"html/template"
+	"net/http"
+	"nistagram/post/dto"
+	"nistagram/util"
+)
+
+func (handler *Handler) ArrangeMedias(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("ArrangeMedias-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+	params := mux.Vars(r)
+	id, err := primitive.ObjectIDFromHex(params["id"])
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var input struct {
+		Medias []dto.MediaDto `json:"medias"`
+	}
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err = handler.PostService.ArrangeMedias(ctx, util.GetLoggedUserIDFromToken(r), id, safeMediaDtos(input.Medias)); err != nil {
+		util.Tracer.LogError(span, err)
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("{\"message\":\"ok\"}"))
+}
+
+func safeMediaDtos(mediaDtos []dto.MediaDto) []dto.MediaDto {
+	for i := range mediaDtos {
+		mediaDtos[i].AltText = template.HTMLEscapeString(mediaDtos[i].AltText)
+	}
+	return mediaDtos
+}
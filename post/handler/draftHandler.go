@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"net/http"
+	"nistagram/post/dto"
+	"nistagram/post/service"
+	"nistagram/util"
+)
+
+func (handler *Handler) GetDrafts(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("GetDrafts-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+
+	page, err := getPage(r)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	result, err := handler.PostService.GetDrafts(ctx, util.GetLoggedUserIDFromToken(r), page)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		fmt.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(&result)
+}
+
+func (handler *Handler) UpdateDraft(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("UpdateDraft-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	params := mux.Vars(r)
+	id, err := primitive.ObjectIDFromHex(params["id"])
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var postDto dto.PostDto
+	if err = json.NewDecoder(r.Body).Decode(&postDto); err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	postDto = safePostDto(postDto)
+
+	switch err = handler.PostService.UpdateDraft(ctx, util.GetLoggedUserIDFromToken(r), id, postDto); err {
+	case nil:
+		w.WriteHeader(http.StatusOK)
+	case mongo.ErrNoDocuments:
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusNotFound)
+	case service.ErrForbidden:
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusForbidden)
+	case service.ErrInvalidInput:
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+	default:
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
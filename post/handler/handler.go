@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -16,10 +15,9 @@ import (
 	"nistagram/post/dto"
 	"nistagram/post/model"
 	"nistagram/post/service"
+	"nistagram/post/visibility"
 	"nistagram/util"
-	"os"
 	"strconv"
-	"strings"
 )
 
 type Handler struct {
@@ -31,7 +29,13 @@ func (handler Handler) GetPublic(w http.ResponseWriter, r *http.Request) {
 	defer util.Tracer.FinishSpan(span)
 
 	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
-	result, err := handler.PostService.GetPublic(ctx, util.GetLoggedUserIDFromToken(r))
+	page, err := getPage(r)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	result, err := handler.PostService.GetPublic(ctx, util.GetLoggedUserIDFromToken(r), page)
 	if err != nil {
 		util.Tracer.LogError(span, err)
 		fmt.Println(err)
@@ -64,7 +68,14 @@ func (handler Handler) GetMyPosts(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := handler.PostService.GetMyPosts(ctx, loggedUserID)
+	page, err := getPage(r)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	result, err := handler.PostService.GetMyPosts(ctx, loggedUserID, page)
 	if err != nil {
 		util.Tracer.LogError(span, err)
 		fmt.Println(err)
@@ -82,6 +93,46 @@ func (handler Handler) GetMyPosts(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write(js)
 }
 
+func (handler Handler) GetStoryArchive(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("GetStoryArchive-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	loggedUserID := util.GetLoggedUserIDFromToken(r)
+	if loggedUserID == 0 {
+		util.Tracer.LogError(span, fmt.Errorf("user is not logged in"))
+		http.Error(w, "user is not logged in", http.StatusForbidden)
+		return
+	}
+
+	page, err := getPage(r)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	result, err := handler.PostService.GetStoryArchive(ctx, loggedUserID, page)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		fmt.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	js, err := json.Marshal(result)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(js)
+}
+
 func (handler Handler) GetPostsForHomePage(w http.ResponseWriter, r *http.Request) {
 	span := util.Tracer.StartSpanFromRequest("GetPostsForHomePage-handler", r)
 	defer util.Tracer.FinishSpan(span)
@@ -97,6 +148,26 @@ func (handler Handler) GetPostsForHomePage(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	mode, ok := model.GetFeedMode(r.URL.Query().Get("mode"))
+	if !ok {
+		util.Tracer.LogError(span, fmt.Errorf("invalid feed mode"))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var page model.Page
+	var rankedPage model.RankedPage
+	var err error
+	if mode == model.RANKED {
+		rankedPage, err = getRankedPage(r)
+	} else {
+		page, err = getPage(r)
+	}
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
 	followingProfiles, err := getFollowingProfiles(ctx, loggedUserID)
 	if err != nil {
 		util.Tracer.LogError(span, err)
@@ -105,7 +176,12 @@ func (handler Handler) GetPostsForHomePage(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	result, err := handler.PostService.GetPostsForHomePage(ctx, followingProfiles, loggedUserID)
+	var result dto.PostPageDTO
+	if mode == model.RANKED {
+		result, err = handler.PostService.GetRankedPostsForHomePage(ctx, followingProfiles, loggedUserID, rankedPage)
+	} else {
+		result, err = handler.PostService.GetPostsForHomePage(ctx, followingProfiles, loggedUserID, page)
+	}
 	if err != nil {
 		util.Tracer.LogError(span, err)
 		fmt.Println(err)
@@ -131,8 +207,13 @@ func (handler Handler) GetProfilesPosts(w http.ResponseWriter, r *http.Request)
 	params := mux.Vars(r)
 	targetUsername := template.HTMLEscapeString(params["username"])
 	loggedUserId := util.GetLoggedUserIDFromToken(r)
+	page, err := getPage(r)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
 	var followingProfiles []util.FollowingProfileDTO
-	var err error
 	if loggedUserId != 0 {
 		followingProfiles, err = getFollowingProfiles(ctx, loggedUserId)
 		if err != nil {
@@ -143,7 +224,7 @@ func (handler Handler) GetProfilesPosts(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	result, err := handler.PostService.GetProfilesPosts(ctx, followingProfiles, targetUsername, loggedUserId)
+	result, err := handler.PostService.GetProfilesPosts(ctx, followingProfiles, targetUsername, loggedUserId, page)
 	if err != nil {
 		util.Tracer.LogError(span, err)
 		fmt.Println(err)
@@ -168,8 +249,14 @@ func (handler *Handler) SearchPublicByLocation(w http.ResponseWriter, r *http.Re
 	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
 	params := mux.Vars(r)
 	location := template.HTMLEscapeString(params["value"])
+	page, err := getPage(r)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
 
-	result, err := handler.PostService.GetPublicPostByLocation(ctx, location, util.GetLoggedUserIDFromToken(r))
+	result, err := handler.PostService.GetPublicPostByLocation(ctx, location, util.GetLoggedUserIDFromToken(r), page)
 	if err != nil {
 		util.Tracer.LogError(span, err)
 		fmt.Println(err)
@@ -192,8 +279,14 @@ func (handler *Handler) SearchPublicByHashTag(w http.ResponseWriter, r *http.Req
 	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
 	params := mux.Vars(r)
 	hashTag := template.HTMLEscapeString(params["value"])
+	page, err := getPage(r)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
 
-	result, err := handler.PostService.GetPublicPostByHashTag(ctx, hashTag, util.GetLoggedUserIDFromToken(r))
+	result, err := handler.PostService.GetPublicPostByHashTag(ctx, hashTag, util.GetLoggedUserIDFromToken(r), page)
 	if err != nil {
 		util.Tracer.LogError(span, err)
 		fmt.Println(err)
@@ -209,13 +302,54 @@ func (handler *Handler) SearchPublicByHashTag(w http.ResponseWriter, r *http.Req
 	w.Header().Set("Content-Type", "application/json")
 }
 
+// SearchPublic does not escape the query like the other searches do, quotes are part of the phrase syntax and the
+// query is only ever matched against, never rendered.
+func (handler *Handler) SearchPublic(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("SearchPublic-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+	page, err := getSearchPage(r)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	result, err := handler.PostService.SearchPublic(ctx, r.URL.Query().Get("q"), util.GetLoggedUserIDFromToken(r), page)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(&result)
+}
+
 func (handler *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	span := util.Tracer.StartSpanFromRequest("Create-handler", r)
 	defer util.Tracer.FinishSpan(span)
 
 	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	handler.createFromForm(ctx, w, r, "nistagram/post/handler.Create", false)
+}
+
+func (handler *Handler) SaveDraft(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("SaveDraft-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	handler.createFromForm(ctx, w, r, "nistagram/post/handler.SaveDraft", true)
+}
+
+// createFromForm reads a multipart post upload; drafts keep the requested draft or scheduled status, everything else is published at once.
+func (handler *Handler) createFromForm(ctx context.Context, w http.ResponseWriter, r *http.Request, methodPath string, draft bool) {
+	span := util.Tracer.StartSpanFromContext(ctx, "createFromForm-handler")
+	defer util.Tracer.FinishSpan(span)
+
+	ctx = util.Tracer.ContextWithSpan(ctx, span)
 	profileId := util.GetLoggedUserIDFromToken(r)
-	methodPath := "nistagram/post/handler.Create"
 	if err := r.ParseMultipartForm(0); err != nil {
 		util.Tracer.LogError(span, err)
 		util.Logging(util.ERROR, methodPath, "", err.Error(), "post")
@@ -233,6 +367,14 @@ func (handler *Handler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	postDto.Medias = safeMediaDtos(postDto.Medias)
+
+	if !draft {
+		postDto.Status = model.PUBLISHED.ToString()
+	} else if model.GetPostStatus(postDto.Status) == model.PUBLISHED {
+		postDto.Status = model.DRAFT.ToString()
+	}
+
 	var files []*multipart.FileHeader
 	for i := 0; ; i++ {
 		if file := r.MultipartForm.File["file"+strconv.Itoa(i)]; len(file) > 0 {
@@ -242,11 +384,31 @@ func (handler *Handler) Create(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	mediaNames := generateMediaNames(files)
-
-	fmt.Println(postDto)
+	medias, err := handler.ingestFiles(ctx, files)
+	switch err {
+	case nil:
+	case service.ErrUnsupportedMedia:
+		util.Tracer.LogError(span, err)
+		util.Logging(util.ERROR, methodPath, util.GetIPAddress(r), "Unsupported media type", "post")
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	case service.ErrMediaTooLarge:
+		util.Tracer.LogError(span, err)
+		util.Logging(util.ERROR, methodPath, util.GetIPAddress(r), "Media too large", "post")
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	default:
+		util.Tracer.LogError(span, err)
+		util.Logging(util.ERROR, methodPath, "", err.Error(), "post")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
 
-	switch err := handler.createPost(ctx, profileId, postDto, mediaNames); err {
+	err = handler.createPost(ctx, profileId, postDto, medias)
+	if err != nil {
+		handler.PostService.DeleteMedia(ctx, medias)
+	}
+	switch err {
 	case nil:
 		w.WriteHeader(http.StatusCreated)
 		util.Logging(util.SUCCESS, methodPath, util.GetIPAddress(r), "Success in creating post, "+util.GetLoggingStringFromID(profileId), "post")
@@ -255,6 +417,11 @@ func (handler *Handler) Create(w http.ResponseWriter, r *http.Request) {
 		util.Logging(util.ERROR, methodPath, util.GetIPAddress(r), "Wrong post type", "post")
 		w.WriteHeader(http.StatusBadRequest)
 		return
+	case service.ErrInvalidInput:
+		util.Tracer.LogError(span, err)
+		util.Logging(util.ERROR, methodPath, util.GetIPAddress(r), "Invalid post data", "post")
+		w.WriteHeader(http.StatusBadRequest)
+		return
 	default:
 		util.Tracer.LogError(span, err)
 		fmt.Println(err)
@@ -262,13 +429,6 @@ func (handler *Handler) Create(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-
-	if err := insertIntoFiles(ctx, files, mediaNames); err != nil {
-		util.Tracer.LogError(span, err)
-		util.Logging(util.ERROR, methodPath, "", err.Error(), "post")
-		w.WriteHeader(http.StatusInternalServerError)
-	}
-
 }
 
 func (handler *Handler) GetPost(w http.ResponseWriter, r *http.Request) {
@@ -285,7 +445,14 @@ func (handler *Handler) GetPost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	switch result, err := handler.PostService.ReadPost(ctx, id); err {
+	policy, err := handler.getViewerPolicy(ctx, r)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		writeServiceError(w, err)
+		return
+	}
+
+	switch result, err := handler.PostService.ReadPost(ctx, policy, id); err {
 	case mongo.ErrNoDocuments:
 		util.Tracer.LogError(span, err)
 		w.WriteHeader(http.StatusNotFound)
@@ -318,7 +485,7 @@ func (handler *Handler) DeletePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = handler.PostService.DeletePost(ctx, id)
+	err = handler.PostService.DeletePost(ctx, id, util.GetLoggedUserIDFromToken(r))
 	if err != nil {
 		util.Tracer.LogError(span, err)
 		fmt.Println(err)
@@ -353,10 +520,16 @@ func (handler *Handler) UpdatePost(w http.ResponseWriter, r *http.Request) {
 
 	postDto = safePostDto(postDto)
 
-	switch err = handler.PostService.UpdatePost(ctx, id, postDto); err {
+	switch err = handler.PostService.UpdatePost(ctx, util.GetLoggedUserIDFromToken(r), id, postDto); err {
 	case mongo.ErrNoDocuments:
 		util.Tracer.LogError(span, err)
 		w.WriteHeader(http.StatusNotFound)
+	case service.ErrForbidden:
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusForbidden)
+	case service.ErrInvalidInput:
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
 	case nil:
 		w.WriteHeader(http.StatusOK)
 	default:
@@ -365,6 +538,48 @@ func (handler *Handler) UpdatePost(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (handler *Handler) GetPostRevisions(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("GetPostRevisions-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+	params := mux.Vars(r)
+	id, err := primitive.ObjectIDFromHex(params["id"])
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	loggedUserId := util.GetLoggedUserIDFromToken(r)
+	var followingProfiles []util.FollowingProfileDTO
+	if loggedUserId != 0 {
+		followingProfiles, err = getFollowingProfiles(ctx, loggedUserId)
+		if err != nil {
+			util.Tracer.LogError(span, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	result, err := handler.PostService.GetPostRevisions(ctx, followingProfiles, loggedUserId, id)
+	switch err {
+	case nil:
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(&result)
+	case mongo.ErrNoDocuments:
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusNotFound)
+	case service.ErrForbidden:
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusForbidden)
+	default:
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
 func (handler *Handler) DeleteUserPosts(w http.ResponseWriter, r *http.Request) {
 	span := util.Tracer.StartSpanFromRequest("DeleteUserPosts-handler", r)
 	defer util.Tracer.FinishSpan(span)
@@ -460,6 +675,12 @@ func (handler *Handler) GetPosts(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	policy, err := handler.getViewerPolicy(ctx, r)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		writeServiceError(w, err)
+		return
+	}
 
 	var posts []model.Post
 	for _, value := range input.Ids {
@@ -470,10 +691,10 @@ func (handler *Handler) GetPosts(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		switch result, err := handler.PostService.ReadPost(ctx, postID); err {
+		switch result, err := handler.PostService.ReadPost(ctx, policy, postID); err {
 		case mongo.ErrNoDocuments:
 			util.Tracer.LogError(span, fmt.Errorf("post not found"))
-			continue //escaping deleted posts
+			continue //escaping deleted and hidden posts
 		case nil:
 			posts = append(posts, result)
 		default:
@@ -519,6 +740,11 @@ func (handler Handler) GetMediaById(w http.ResponseWriter, r *http.Request) {
 	mediaId := template.HTMLEscapeString(params["id"])
 
 	result, err := handler.PostService.GetMediaById(ctx, mediaId)
+	if err == mongo.ErrNoDocuments {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		util.Tracer.LogError(span, err)
 		fmt.Println(err)
@@ -562,17 +788,17 @@ func (handler *Handler) GetPostById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := handler.PostService.ReadPost(ctx, id)
+	policy, err := handler.PostService.GetVisibilityPolicy(ctx, followingProfiles, loggedUserId)
 	if err != nil {
 		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
 	}
-
-	if result.IsPrivate {
-		if !util.IsFollowed(followingProfiles, result.PublisherId) {
-			fmt.Println("Publisher is not followed by logged user!")
-			w.WriteHeader(http.StatusBadRequest)
-			return
-		}
+	result, err := handler.PostService.ReadPost(ctx, policy, id)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		writeServiceError(w, err)
+		return
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -580,6 +806,57 @@ func (handler *Handler) GetPostById(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 }
 
+func getPage(r *http.Request) (model.Page, error) {
+	limit, err := getPageLimit(r)
+	if err != nil {
+		return model.Page{}, err
+	}
+	cursor, err := model.DecodeCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		return model.Page{}, err
+	}
+	return model.Page{Cursor: cursor, Limit: limit}, nil
+}
+
+func getRankedPage(r *http.Request) (model.RankedPage, error) {
+	limit, err := getPageLimit(r)
+	if err != nil {
+		return model.RankedPage{}, err
+	}
+	cursor, err := model.DecodeRankedCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		return model.RankedPage{}, err
+	}
+	return model.RankedPage{Cursor: cursor, Limit: limit}, nil
+}
+
+func getSearchPage(r *http.Request) (model.SearchPage, error) {
+	limit, err := getPageLimit(r)
+	if err != nil {
+		return model.SearchPage{}, err
+	}
+	cursor, err := model.DecodeSearchCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		return model.SearchPage{}, err
+	}
+	return model.SearchPage{Cursor: cursor, Limit: limit}, nil
+}
+
+func getPageLimit(r *http.Request) (int, error) {
+	limit := r.URL.Query().Get("limit")
+	if limit == "" {
+		return model.DefaultPageSize, nil
+	}
+	value, err := strconv.Atoi(limit)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("invalid page size")
+	}
+	if value > model.MaxPageSize {
+		value = model.MaxPageSize
+	}
+	return value, nil
+}
+
 func safePostDto(postDto dto.PostDto) dto.PostDto {
 	postDto.Description = template.HTMLEscapeString(postDto.Description)
 	postDto.HashTags = template.HTMLEscapeString(postDto.HashTags)
@@ -614,6 +891,31 @@ func getFollowingProfiles(ctx context.Context, loggedUserId uint) ([]util.Follow
 	return followingProfiles, err
 }
 
+// getViewerPolicy reads posts on behalf of the profile in the viewer query parameter, or on behalf of the calling
+// service when there is none. A viewer that is not a profile ID is invalid input.
+func (handler *Handler) getViewerPolicy(ctx context.Context, r *http.Request) (visibility.Policy, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "getViewerPolicy-handler")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	viewer := r.URL.Query().Get("viewer")
+	if viewer == "" {
+		return visibility.ForService(), nil
+	}
+	viewerID, err := strconv.ParseUint(viewer, 10, 64)
+	if err != nil || viewerID == 0 {
+		util.Tracer.LogError(span, fmt.Errorf("invalid viewer %q", viewer))
+		return visibility.Policy{}, service.ErrInvalidInput
+	}
+	followingProfiles, err := getFollowingProfiles(nextCtx, uint(viewerID))
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return visibility.Policy{}, err
+	}
+	return handler.PostService.GetVisibilityPolicy(nextCtx, followingProfiles, uint(viewerID))
+}
+
 func getProfileByProfileId(ctx context.Context, profileId uint) (*http.Response, error) {
 	span := util.Tracer.StartSpanFromContext(ctx, "getProfileByProfileId-handler")
 	defer util.Tracer.FinishSpan(span)
@@ -625,7 +927,7 @@ func getProfileByProfileId(ctx context.Context, profileId uint) (*http.Response,
 	return resp, err
 }
 
-func (handler *Handler) createPost(ctx context.Context, profileId uint, postDto dto.PostDto, mediaNames []string) error {
+func (handler *Handler) createPost(ctx context.Context, profileId uint, postDto dto.PostDto, medias []model.Media) error {
 	span := util.Tracer.StartSpanFromContext(ctx, "createPost-handler")
 	defer util.Tracer.FinishSpan(span)
 
@@ -653,51 +955,35 @@ func (handler *Handler) createPost(ctx context.Context, profileId uint, postDto
 	}
 
 	profile.ProfileId = profileId
-	if err := handler.PostService.CreatePost(nextCtx, postType, postDto, mediaNames, profile); err != nil {
+	if err := handler.PostService.CreatePost(nextCtx, postType, postDto, medias, profile); err != nil {
 		util.Tracer.LogError(span, err)
 		return err
 	}
 	return nil
 }
 
-func generateMediaNames(files []*multipart.FileHeader) []string {
-	var mediaNames []string
-	for i := 0; i < len(files); i++ {
-		uid := uuid.NewString()
-		fn := strings.Split(files[i].Filename, ".")
-		mediaNames = append(mediaNames, uid+"."+fn[1])
-	}
-	return mediaNames
-}
-
-func insertIntoFiles(ctx context.Context, files []*multipart.FileHeader, mediaNames []string) error {
-	span := util.Tracer.StartSpanFromContext(ctx, "insertIntoFiles-handler")
+func (handler *Handler) ingestFiles(ctx context.Context, files []*multipart.FileHeader) ([]model.Media, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "ingestFiles-handler")
 	defer util.Tracer.FinishSpan(span)
 
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	medias := make([]model.Media, 0)
 	for i := 0; i < len(files); i++ {
 		file, err := files[i].Open()
 		if err != nil {
 			util.Tracer.LogError(span, err)
-			return err
+			handler.PostService.DeleteMedia(nextCtx, medias)
+			return nil, err
 		}
-
-		f, err := os.OpenFile("../../nistagramstaticdata/data/"+mediaNames[i], os.O_WRONLY|os.O_CREATE, 0666)
+		media, err := handler.PostService.IngestMedia(nextCtx, file)
+		_ = file.Close()
 		if err != nil {
 			util.Tracer.LogError(span, err)
-			return err
-		}
-		if _, err = io.Copy(f, file); err != nil {
-			util.Tracer.LogError(span, err)
-			return err
-		}
-		if err = f.Close(); err != nil {
-			util.Tracer.LogError(span, err)
-			return err
-		}
-		if err = file.Close(); err != nil {
-			util.Tracer.LogError(span, err)
-			return err
+			handler.PostService.DeleteMedia(nextCtx, medias)
+			return nil, err
 		}
+		medias = append(medias, media)
 	}
-	return nil
+	return medias, nil
 }
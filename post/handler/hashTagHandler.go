@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/mongo"
+	"net/http"
+	"nistagram/util"
+	"strconv"
+	"time"
+)
+
+const defaultTrendingWindow = 24 * time.Hour
+const maxTrendingWindow = 30 * 24 * time.Hour
+const defaultTrendingLimit = 10
+const maxTrendingLimit = 50
+
+func (handler *Handler) SuggestHashTags(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("SuggestHashTags-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+
+	result, err := handler.PostService.SuggestHashTags(ctx, r.URL.Query().Get("q"))
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		fmt.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(&result)
+}
+
+func (handler *Handler) GetTrendingHashTags(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("GetTrendingHashTags-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+
+	window := defaultTrendingWindow
+	if value := r.URL.Query().Get("window"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 || parsed > maxTrendingWindow {
+			util.Tracer.LogError(span, fmt.Errorf("invalid window"))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		window = parsed
+	}
+	limit := defaultTrendingLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			util.Tracer.LogError(span, fmt.Errorf("invalid limit"))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if parsed > maxTrendingLimit {
+			parsed = maxTrendingLimit
+		}
+		limit = parsed
+	}
+
+	result, err := handler.PostService.GetTrendingHashTags(ctx, window, limit)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		fmt.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(&result)
+}
+
+func (handler *Handler) GetHashTag(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("GetHashTag-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+	params := mux.Vars(r)
+
+	switch result, err := handler.PostService.GetHashTag(ctx, params["name"]); err {
+	case mongo.ErrNoDocuments:
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusNotFound)
+	case nil:
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(&result)
+	default:
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"html/template"
+	"net/http"
+	"nistagram/post/dto"
+	"nistagram/post/service"
+	"nistagram/util"
+)
+
+func (handler *Handler) CreateHighlight(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("CreateHighlight-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+
+	var highlightDto dto.HighlightDto
+	if err := json.NewDecoder(r.Body).Decode(&highlightDto); err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	highlightDto.Title = template.HTMLEscapeString(highlightDto.Title)
+
+	result, err := handler.PostService.CreateHighlight(ctx, util.GetLoggedUserIDFromToken(r), highlightDto)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(&result)
+}
+
+func (handler *Handler) UpdateHighlight(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("UpdateHighlight-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+	params := mux.Vars(r)
+	id, err := primitive.ObjectIDFromHex(params["id"])
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var highlightDto dto.HighlightDto
+	if err = json.NewDecoder(r.Body).Decode(&highlightDto); err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	highlightDto.Title = template.HTMLEscapeString(highlightDto.Title)
+
+	result, err := handler.PostService.UpdateHighlight(ctx, util.GetLoggedUserIDFromToken(r), id, highlightDto)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(&result)
+}
+
+func (handler *Handler) DeleteHighlight(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("DeleteHighlight-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+	params := mux.Vars(r)
+	id, err := primitive.ObjectIDFromHex(params["id"])
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err = handler.PostService.DeleteHighlight(ctx, util.GetLoggedUserIDFromToken(r), id); err != nil {
+		util.Tracer.LogError(span, err)
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("{\"message\":\"ok\"}"))
+}
+
+func (handler *Handler) GetProfileHighlights(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("GetProfileHighlights-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+	params := mux.Vars(r)
+	targetUsername := template.HTMLEscapeString(params["username"])
+	loggedUserId := util.GetLoggedUserIDFromToken(r)
+	var followingProfiles []util.FollowingProfileDTO
+	var err error
+	if loggedUserId != 0 {
+		followingProfiles, err = getFollowingProfiles(ctx, loggedUserId)
+		if err != nil {
+			util.Tracer.LogError(span, err)
+			fmt.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	result, err := handler.PostService.GetProfileHighlights(ctx, followingProfiles, targetUsername, loggedUserId)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		fmt.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if js, err := json.Marshal(result); err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusInternalServerError)
+	} else {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(js)
+	}
+}
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch err {
+	case mongo.ErrNoDocuments:
+		w.WriteHeader(http.StatusNotFound)
+	case service.ErrForbidden:
+		w.WriteHeader(http.StatusForbidden)
+	case service.ErrInvalidInput:
+		w.WriteHeader(http.StatusBadRequest)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"net/http"
+	"nistagram/util"
+	"time"
+)
+
+// Insights cover the last defaultInsightsDays days unless the request names its own from and to days, both included.
+const defaultInsightsDays = 30
+
+func (handler *Handler) GetPostInsights(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("GetPostInsights-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+	params := mux.Vars(r)
+	id, err := primitive.ObjectIDFromHex(params["id"])
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	from, to, err := getInsightsPeriod(r)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	insights, err := handler.PostService.GetPostInsights(ctx, util.GetLoggedUserIDFromToken(r), id, from, to)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(insights)
+}
+
+func (handler *Handler) RecordProfileVisit(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("RecordProfileVisit-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+	params := mux.Vars(r)
+	id, err := primitive.ObjectIDFromHex(params["id"])
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	loggedUserID := util.GetLoggedUserIDFromToken(r)
+	followingProfiles, err := getFollowingProfiles(ctx, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if err = handler.PostService.RecordProfileVisit(ctx, followingProfiles, loggedUserID, id); err != nil {
+		util.Tracer.LogError(span, err)
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("{\"message\":\"ok\"}"))
+}
+
+// getInsightsPeriod reads the from and to days of the request as the UTC period [from, to + 1 day).
+func getInsightsPeriod(r *http.Request) (time.Time, time.Time, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	from, to := today.AddDate(0, 0, 1-defaultInsightsDays), today
+	var err error
+	if value := r.URL.Query().Get("from"); value != "" {
+		if from, err = time.Parse("2006-01-02", value); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if value := r.URL.Query().Get("to"); value != "" {
+		if to, err = time.Parse("2006-01-02", value); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	return from, to.AddDate(0, 0, 1), nil
+}
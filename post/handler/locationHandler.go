@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"html/template"
+	"net/http"
+	"nistagram/post/model"
+	"nistagram/util"
+	"strconv"
+)
+
+const defaultNearbyRadius = 1000
+const maxNearbyRadius = 50000
+
+func (handler *Handler) GetPublicNearby(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("GetPublicNearby-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+
+	point, radius, err := getNearbyParams(r)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	page, err := getPage(r)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	result, err := handler.PostService.GetPublicNearby(ctx, point, radius, util.GetLoggedUserIDFromToken(r), page)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		fmt.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(&result)
+}
+
+func (handler *Handler) GetPublicPlaces(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("GetPublicPlaces-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+
+	point, radius, err := getNearbyParams(r)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	result, err := handler.PostService.GetPublicPlaces(ctx, point, radius, util.GetLoggedUserIDFromToken(r))
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		fmt.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(&result)
+}
+
+func (handler *Handler) GetPublicByPlace(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("GetPublicByPlace-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+	params := mux.Vars(r)
+	name := template.HTMLEscapeString(params["name"])
+
+	page, err := getPage(r)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	result, err := handler.PostService.GetPublicByPlace(ctx, name, util.GetLoggedUserIDFromToken(r), page)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		fmt.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(&result)
+}
+
+func getNearbyParams(r *http.Request) (model.GeoPoint, float64, error) {
+	query := r.URL.Query()
+	latitude, err := strconv.ParseFloat(query.Get("lat"), 64)
+	if err != nil {
+		return model.GeoPoint{}, 0, fmt.Errorf("invalid latitude")
+	}
+	longitude, err := strconv.ParseFloat(query.Get("lng"), 64)
+	if err != nil {
+		return model.GeoPoint{}, 0, fmt.Errorf("invalid longitude")
+	}
+	if !model.IsValidCoordinate(latitude, longitude) {
+		return model.GeoPoint{}, 0, fmt.Errorf("invalid coordinates")
+	}
+	radius := float64(defaultNearbyRadius)
+	if value := query.Get("radius"); value != "" {
+		radius, err = strconv.ParseFloat(value, 64)
+		if err != nil || radius <= 0 {
+			return model.GeoPoint{}, 0, fmt.Errorf("invalid radius")
+		}
+		if radius > maxNearbyRadius {
+			radius = maxNearbyRadius
+		}
+	}
+	return model.NewGeoPoint(latitude, longitude), radius, nil
+}
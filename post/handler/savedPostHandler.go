@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"html/template"
+	"net/http"
+	"nistagram/post/dto"
+	"nistagram/util"
+)
+
+func (handler *Handler) SavePost(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("SavePost-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+
+	var savePostDto dto.SavePostDto
+	if err := json.NewDecoder(r.Body).Decode(&savePostDto); err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	loggedUserID := util.GetLoggedUserIDFromToken(r)
+	followingProfiles, err := getFollowingProfiles(ctx, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if err = handler.PostService.SavePost(ctx, followingProfiles, loggedUserID, savePostDto); err != nil {
+		util.Tracer.LogError(span, err)
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (handler *Handler) UnsavePost(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("UnsavePost-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+	params := mux.Vars(r)
+	postID, err := primitive.ObjectIDFromHex(params["postId"])
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = handler.PostService.UnsavePost(ctx, util.GetLoggedUserIDFromToken(r), postID, r.URL.Query().Get("collection"))
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (handler *Handler) GetSavedPosts(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("GetSavedPosts-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+
+	page, err := getPage(r)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	loggedUserID := util.GetLoggedUserIDFromToken(r)
+	followingProfiles, err := getFollowingProfiles(ctx, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		fmt.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	result, err := handler.PostService.GetSavedPosts(ctx, followingProfiles, loggedUserID, r.URL.Query().Get("collection"), page)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(&result)
+}
+
+func (handler *Handler) CreateBookmarkCollection(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("CreateBookmarkCollection-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+
+	var collectionDto dto.BookmarkCollectionDto
+	if err := json.NewDecoder(r.Body).Decode(&collectionDto); err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	collectionDto.Name = template.HTMLEscapeString(collectionDto.Name)
+
+	result, err := handler.PostService.CreateBookmarkCollection(ctx, util.GetLoggedUserIDFromToken(r), collectionDto)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(&result)
+}
+
+func (handler *Handler) GetBookmarkCollections(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("GetBookmarkCollections-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+
+	result, err := handler.PostService.GetBookmarkCollections(ctx, util.GetLoggedUserIDFromToken(r))
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(&result)
+}
+
+func (handler *Handler) DeleteBookmarkCollection(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("DeleteBookmarkCollection-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+	params := mux.Vars(r)
+	id, err := primitive.ObjectIDFromHex(params["id"])
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err = handler.PostService.DeleteBookmarkCollection(ctx, util.GetLoggedUserIDFromToken(r), id); err != nil {
+		util.Tracer.LogError(span, err)
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"github.com/gorilla/mux"
+	"net/http"
+	"nistagram/util"
+)
+
+func (handler *Handler) FollowPublisher(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("FollowPublisher-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	profileID, publisherID, err := getTimelineParams(r)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err = handler.PostService.FollowPublisher(ctx, profileID, publisherID); err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (handler *Handler) UnfollowPublisher(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("UnfollowPublisher-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	profileID, publisherID, err := getTimelineParams(r)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err = handler.PostService.UnfollowPublisher(ctx, profileID, publisherID); err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func getTimelineParams(r *http.Request) (uint, uint, error) {
+	params := mux.Vars(r)
+	profileID := util.String2Uint(params["profileId"])
+	publisherID := util.String2Uint(params["publisherId"])
+	if profileID == 0 || publisherID == 0 {
+		return 0, 0, fmt.Errorf("invalid profile id")
+	}
+	return profileID, publisherID, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/go-redis/redis/v8"
 	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"net/http"
@@ -12,8 +13,10 @@ import (
 	"nistagram/post/repository"
 	"nistagram/post/service"
 	"nistagram/util"
+	"nistagram/util/mediastore"
 	"nistagram/util/saga"
 	"os"
+	"strconv"
 	"time"
 )
 
@@ -52,8 +55,63 @@ func initDB() *mongo.Client {
 
 func initCollections(client *mongo.Client) {
 	const postsCollectionName = "posts"
+	const storyArchiveCollectionName = "storyarchive"
+	const highlightsCollectionName = "highlights"
+	const hashTagsCollectionName = "hashtags"
+	const postRevisionsCollectionName = "postrevisions"
+	const savedPostsCollectionName = "savedposts"
+	const bookmarkCollectionsCollectionName = "bookmarkcollections"
+	const storyViewsCollectionName = "storyviews"
 	const postDbName = "postdb"
 	createCollection(client, postDbName, postsCollectionName)
+	createCollection(client, postDbName, storyArchiveCollectionName)
+	createCollection(client, postDbName, highlightsCollectionName)
+	createCollection(client, postDbName, hashTagsCollectionName)
+	createCollection(client, postDbName, postRevisionsCollectionName)
+	createCollection(client, postDbName, savedPostsCollectionName)
+	createCollection(client, postDbName, bookmarkCollectionsCollectionName)
+	createCollection(client, postDbName, storyViewsCollectionName)
+	createIndexes(client, postDbName, postsCollectionName, []mongo.IndexModel{
+		{Keys: bson.D{{"isdeleted", 1}, {"isprivate", 1}, {"publishdate", -1}, {"_id", -1}}},
+		{Keys: bson.D{{"publisherid", 1}, {"isdeleted", 1}, {"publishdate", -1}, {"_id", -1}}},
+		{Keys: bson.D{{"publisherusername", 1}, {"isdeleted", 1}, {"publishdate", -1}, {"_id", -1}}},
+		{Keys: bson.D{{"posttype", 1}, {"expiresat", 1}}},
+		{Keys: bson.D{{"tags", 1}, {"publishdate", -1}, {"_id", -1}}},
+		{Keys: bson.D{{"geolocation.point", "2dsphere"}}},
+		{Keys: bson.D{{"geolocation.name", 1}, {"publishdate", -1}, {"_id", -1}}},
+		{Keys: bson.D{{"status", 1}, {"scheduledat", 1}}},
+		{Keys: bson.D{{"isdeleted", 1}, {"deletedat", 1}}},
+		{Keys: bson.D{{"usertags.profileid", 1}, {"publishdate", -1}, {"_id", -1}}},
+		{Keys: bson.D{{"description", "text"}, {"hashtags", "text"}, {"location", "text"}, {"publisherusername", "text"}},
+			Options: options.Index().SetName("post_text_search").
+				SetWeights(bson.D{{"description", 10}, {"hashtags", 5}, {"location", 3}, {"publisherusername", 2}})},
+	})
+	createIndexes(client, postDbName, storyArchiveCollectionName, []mongo.IndexModel{
+		{Keys: bson.D{{"publisherid", 1}, {"publishdate", -1}, {"_id", -1}}},
+		{Keys: bson.D{{"archivedat", 1}}},
+	})
+	createIndexes(client, postDbName, hashTagsCollectionName, []mongo.IndexModel{
+		{Keys: bson.D{{"postcount", -1}}},
+	})
+	createIndexes(client, postDbName, highlightsCollectionName, []mongo.IndexModel{
+		{Keys: bson.D{{"profileid", 1}, {"createdat", 1}}},
+		{Keys: bson.D{{"storyids", 1}}},
+	})
+	createIndexes(client, postDbName, postRevisionsCollectionName, []mongo.IndexModel{
+		{Keys: bson.D{{"postid", 1}, {"editedat", -1}}},
+	})
+	createIndexes(client, postDbName, savedPostsCollectionName, []mongo.IndexModel{
+		{Keys: bson.D{{"profileid", 1}, {"postid", 1}, {"collectionid", 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{"profileid", 1}, {"collectionid", 1}, {"savedat", -1}, {"_id", -1}}},
+	})
+	createIndexes(client, postDbName, bookmarkCollectionsCollectionName, []mongo.IndexModel{
+		{Keys: bson.D{{"profileid", 1}, {"createdat", 1}}},
+	})
+	createIndexes(client, postDbName, storyViewsCollectionName, []mongo.IndexModel{
+		{Keys: bson.D{{"storyid", 1}, {"viewerid", 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{"storyid", 1}, {"viewedat", -1}}},
+		{Keys: bson.D{{"expiresat", 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
 }
 func createCollection(client *mongo.Client,dbName string, collectionName string) {
 	if err := client.Database(dbName).CreateCollection(context.TODO(), collectionName); err != nil {
@@ -63,12 +121,50 @@ func createCollection(client *mongo.Client,dbName string, collectionName string)
 	}
 }
 
+func createIndexes(client *mongo.Client, dbName string, collectionName string, indexes []mongo.IndexModel) {
+	if _, err := client.Database(dbName).Collection(collectionName).Indexes().CreateMany(context.TODO(), indexes); err != nil {
+		fmt.Println(err)
+	} else {
+		fmt.Println("Create " + collectionName + " indexes success")
+	}
+}
+
+func initTimelineCache() *redis.Client {
+	var redisHost, redisPort = "localhost", "6379" // dev.db environment
+	if util.DockerChecker() {
+		redisHost = "message_broker"
+	}
+	for {
+		client := redis.NewClient(&redis.Options{
+			Addr:     redisHost + ":" + redisPort,
+			Password: "helloworld",
+			DB:       1,
+		})
+
+		if err := client.Ping(context.TODO()).Err(); err != nil {
+			fmt.Println("Cannot connect to the timeline cache! Sleeping 10s and then retrying....")
+			time.Sleep(10 * time.Second)
+		} else {
+			fmt.Println("Connected to the timeline cache")
+			return client
+		}
+	}
+}
+
 func initPostRepo(client *mongo.Client) *repository.PostRepository {
 	return &repository.PostRepository{Client: client}
 }
 
-func initService(postRepo *repository.PostRepository) *service.PostService {
-	return &service.PostService{PostRepository: postRepo}
+func initTimelineRepo(client *redis.Client) *repository.TimelineRepository {
+	return &repository.TimelineRepository{Client: client}
+}
+
+func initService(postRepo *repository.PostRepository, timelineRepo *repository.TimelineRepository) *service.PostService {
+	return &service.PostService{PostRepository: postRepo,
+		MediaStore:         mediastore.NewFromEnv("../../nistagramstaticdata/data", "/static/data"),
+		TrashRetention:     getTrashRetention(),
+		Timeline:           timelineRepo,
+		CelebrityThreshold: getCelebrityThreshold()}
 }
 func initHandler(postService *service.PostService) *handler.Handler {
 	return &handler.Handler{PostService: postService}
@@ -79,24 +175,75 @@ func handleFunc(handler *handler.Handler) {
 	util.InitMonitoring("post", router)
 
 	router.HandleFunc("/profile/{username}", handler.GetProfilesPosts).Methods("GET")            // frontend func
+	router.HandleFunc("/profile/{username}/highlights", handler.GetProfileHighlights).Methods("GET") // frontend func
+	router.HandleFunc("/profile/{username}/tagged", handler.GetTaggedPosts).Methods("GET")           // frontend func
+	router.HandleFunc("/highlight",
+		util.RBAC(handler.CreateHighlight, "CREATE_POST", false)).Methods("POST") // frontend func
+	router.HandleFunc("/highlight/{id}",
+		util.RBAC(handler.UpdateHighlight, "CREATE_POST", false)).Methods("PUT") // frontend func
+	router.HandleFunc("/highlight/{id}",
+		util.RBAC(handler.DeleteHighlight, "CREATE_POST", false)).Methods("DELETE") // frontend func
+	router.HandleFunc("/saved",
+		util.RBAC(handler.GetSavedPosts, "READ_NOT_ONLY_PUBLIC_POSTS", false)).Methods("GET") // frontend func
+	router.HandleFunc("/saved",
+		util.RBAC(handler.SavePost, "READ_NOT_ONLY_PUBLIC_POSTS", false)).Methods("POST") // frontend func
+	router.HandleFunc("/saved/collections",
+		util.RBAC(handler.GetBookmarkCollections, "READ_NOT_ONLY_PUBLIC_POSTS", false)).Methods("GET") // frontend func
+	router.HandleFunc("/saved/collections",
+		util.RBAC(handler.CreateBookmarkCollection, "READ_NOT_ONLY_PUBLIC_POSTS", false)).Methods("POST") // frontend func
+	router.HandleFunc("/saved/collections/{id}",
+		util.RBAC(handler.DeleteBookmarkCollection, "READ_NOT_ONLY_PUBLIC_POSTS", false)).Methods("DELETE") // frontend func
+	router.HandleFunc("/saved/{postId}",
+		util.RBAC(handler.UnsavePost, "READ_NOT_ONLY_PUBLIC_POSTS", false)).Methods("DELETE") // frontend func
 	router.HandleFunc("/public", handler.GetPublic).Methods("GET")                               // frontend func
 	router.HandleFunc("/public/location/{value}", handler.SearchPublicByLocation).Methods("GET") // frontend func
 	router.HandleFunc("/public/hashtag/{value}", handler.SearchPublicByHashTag).Methods("GET")   // frontend func
+	router.HandleFunc("/public/search", handler.SearchPublic).Methods("GET")                      // frontend func
+	router.HandleFunc("/public/nearby", handler.GetPublicNearby).Methods("GET")                   // frontend func
+	router.HandleFunc("/public/places", handler.GetPublicPlaces).Methods("GET")                   // frontend func
+	router.HandleFunc("/public/place/{name}", handler.GetPublicByPlace).Methods("GET")            // frontend func
+	router.HandleFunc("/hashtags/suggest", handler.SuggestHashTags).Methods("GET")                // frontend func
+	router.HandleFunc("/hashtags/trending", handler.GetTrendingHashTags).Methods("GET")           // frontend func
+	router.HandleFunc("/hashtags/{name}", handler.GetHashTag).Methods("GET")                      // frontend func
 	router.HandleFunc("/my",
 		util.RBAC(handler.GetMyPosts, "READ_NOT_ONLY_PUBLIC_POSTS", true)).Methods("GET") // frontend func
 	router.HandleFunc("/agent-my", util.AgentAuth(handler.GetMyPosts)).Methods("GET") // frontend func
+	router.HandleFunc("/story-archive",
+		util.RBAC(handler.GetStoryArchive, "READ_NOT_ONLY_PUBLIC_POSTS", false)).Methods("GET") // frontend func
 	router.HandleFunc("/homePage",
 		util.RBAC(handler.GetPostsForHomePage, "READ_NOT_ONLY_PUBLIC_POSTS", true)).Methods("GET") // frontend func
 	router.HandleFunc("/",
 		util.RBAC(handler.Create, "CREATE_POST", false)).Methods("POST") // frontend func
+	router.HandleFunc("/draft",
+		util.RBAC(handler.SaveDraft, "CREATE_POST", false)).Methods("POST") // frontend func
+	router.HandleFunc("/drafts",
+		util.RBAC(handler.GetDrafts, "CREATE_POST", false)).Methods("GET") // frontend func
+	router.HandleFunc("/draft/{id}",
+		util.RBAC(handler.UpdateDraft, "CREATE_POST", false)).Methods("PUT") // frontend func
+	router.HandleFunc("/story/{id}/view",
+		util.RBAC(handler.ViewStory, "READ_NOT_ONLY_PUBLIC_POSTS", false)).Methods("POST") // frontend func
+	router.HandleFunc("/story/{id}/viewers",
+		util.RBAC(handler.GetStoryViewers, "CREATE_POST", false)).Methods("GET") // frontend func
+	router.HandleFunc("/trash",
+		util.RBAC(handler.GetTrash, "CREATE_POST", false)).Methods("GET") // frontend func
+	router.HandleFunc("/trash/{id}/restore",
+		util.RBAC(handler.RestorePost, "CREATE_POST", false)).Methods("PUT") // frontend func
+	router.HandleFunc("/my/{id}",
+		util.RBAC(handler.DeleteOwnPost, "CREATE_POST", false)).Methods("DELETE") // frontend func
 	router.HandleFunc("/user/{loggedUserId}/privacy",
 		util.MSAuth(handler.ChangePrivacy, []string{"profile"})).Methods("PUT")
 	router.HandleFunc("/user/{id}",
 		util.MSAuth(handler.DeleteUserPosts, []string{"profile"})).Methods("DELETE")
 	router.HandleFunc("/user/{loggedUserId}/username",
 		util.MSAuth(handler.ChangeUsername, []string{"profile"})).Methods("PUT")
+	router.HandleFunc("/timeline/{profileId}/following/{publisherId}",
+		util.MSAuth(handler.FollowPublisher, []string{"connection"})).Methods("PUT")
+	router.HandleFunc("/timeline/{profileId}/following/{publisherId}",
+		util.MSAuth(handler.UnfollowPublisher, []string{"connection"})).Methods("DELETE")
 	router.HandleFunc("/post/{id}",
 		util.MSAuth(handler.GetPost, []string{"postreaction"})).Methods("GET")
+	router.HandleFunc("/moderation/{id}",
+		util.MSAuth(handler.RemovePost, []string{"postreaction"})).Methods("DELETE")
 	router.HandleFunc("/read-post/{id}",
 		util.RBAC(handler.GetPostById, "READ_NOT_ONLY_PUBLIC_POSTS", false)).Methods("GET") // frontend func
 	router.HandleFunc("/posts",
@@ -105,7 +252,21 @@ func handleFunc(handler *handler.Handler) {
 		util.MSAuth(handler.MakeCampaign, []string{"campaign"})).Methods("POST")
 	router.HandleFunc("/{id}",
 		util.RBAC(handler.DeletePost, "DELETE_POST", false)).Methods("DELETE") // frontend func
-	router.HandleFunc("/{id}", handler.UpdatePost).Methods("PUT")
+	router.HandleFunc("/{id}",
+		util.RBAC(handler.UpdatePost, "CREATE_POST", false)).Methods("PUT") // frontend func
+	router.HandleFunc("/{id}/revisions", handler.GetPostRevisions).Methods("GET") // frontend func
+	router.HandleFunc("/{id}/insights",
+		util.RBAC(handler.GetPostInsights, "CREATE_POST", false)).Methods("GET") // frontend func
+	router.HandleFunc("/{id}/profile-visit",
+		util.RBAC(handler.RecordProfileVisit, "READ_NOT_ONLY_PUBLIC_POSTS", false)).Methods("POST") // frontend func
+	router.HandleFunc("/{id}/sensitive",
+		util.RBAC(handler.MarkSensitive, "DELETE_POST", false)).Methods("PUT") // frontend func
+	router.HandleFunc("/{id}/medias",
+		util.RBAC(handler.ArrangeMedias, "CREATE_POST", false)).Methods("PUT") // frontend func
+	router.HandleFunc("/{id}/tag",
+		util.RBAC(handler.ApproveUserTag, "READ_NOT_ONLY_PUBLIC_POSTS", false)).Methods("PUT") // frontend func
+	router.HandleFunc("/{id}/tag",
+		util.RBAC(handler.RemoveUserTag, "READ_NOT_ONLY_PUBLIC_POSTS", false)).Methods("DELETE") // frontend func
 	router.HandleFunc("/media/{id}",
 		util.MSAuth(handler.GetMediaById, []string{"monitoring"})).Methods("GET")
 	fmt.Println("Starting server..")
@@ -147,14 +308,100 @@ func initSagaHandlers(handler *handler.Handler) []saga.ChannelHandler {
 	return temp
 }
 
+func getStoryArchiveRetention() time.Duration {
+	retention, err := time.ParseDuration(os.Getenv("STORY_ARCHIVE_RETENTION"))
+	if err != nil || retention <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return retention
+}
+
+func getTrashRetention() time.Duration {
+	retention, err := time.ParseDuration(os.Getenv("POST_TRASH_RETENTION"))
+	if err != nil || retention <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return retention
+}
+
+// getCelebrityThreshold is the number of followers above which the posts of a profile are no longer pushed to the
+// timelines of its followers.
+func getCelebrityThreshold() int {
+	threshold, err := strconv.Atoi(os.Getenv("TIMELINE_CELEBRITY_THRESHOLD"))
+	if err != nil || threshold <= 0 {
+		return 10000
+	}
+	return threshold
+}
+
+func runStoryLifecycle(postService *service.PostService) {
+	retention := getStoryArchiveRetention()
+	if err := postService.BackfillStoryExpiry(context.Background()); err != nil {
+		fmt.Println(err)
+	}
+	for range time.Tick(time.Minute) {
+		if err := postService.ArchiveExpiredStories(context.Background()); err != nil {
+			fmt.Println(err)
+		}
+		if err := postService.PurgeArchivedStories(context.Background(), retention); err != nil {
+			fmt.Println(err)
+		}
+	}
+}
+
+func runScheduledPublisher(postService *service.PostService) {
+	for range time.Tick(10 * time.Second) {
+		if err := postService.PublishScheduledPosts(context.Background()); err != nil {
+			fmt.Println(err)
+		}
+	}
+}
+
+func runTrashPurge(postService *service.PostService) {
+	if err := postService.BackfillDeletedAt(context.Background()); err != nil {
+		fmt.Println(err)
+	}
+	for range time.Tick(time.Hour) {
+		if err := postService.PurgeDeletedPosts(context.Background()); err != nil {
+			fmt.Println(err)
+		}
+	}
+}
+
+// runUserTagBackfill retries the user tag backfill every minute until it goes through, as it needs the profile
+// service, which may start after this one.
+func runUserTagBackfill(postService *service.PostService) {
+	for {
+		err := postService.BackfillUserTags(context.Background())
+		if err == nil {
+			return
+		}
+		fmt.Println(err)
+		time.Sleep(time.Minute)
+	}
+}
+
 func main() {
 	util.TracerInit("post")
 	client := initDB()
 	defer closeConnection(client)
 	postRepo := initPostRepo(client)
-	postService := initService(postRepo)
+	timelineRepo := initTimelineRepo(initTimelineCache())
+	postService := initService(postRepo, timelineRepo)
 	postHandler := initHandler(postService)
 	go saga.SubscribeAndRunPubSubHandlers(nil, initSagaHandlers(postHandler)...)
+	go runStoryLifecycle(postService)
+	go runScheduledPublisher(postService)
+	go runTrashPurge(postService)
+	go runUserTagBackfill(postService)
+	go func() {
+		if err := postService.BackfillHashTags(context.Background()); err != nil {
+			fmt.Println(err)
+		}
+		if err := postService.BackfillMedias(context.Background()); err != nil {
+			fmt.Println(err)
+		}
+	}()
 	_ = util.SetupMSAuth("post")
 	handleFunc(postHandler)
 }
@@ -0,0 +1,8 @@
+package model
+
+import "time"
+
+type ArchivedStory struct {
+	Post       `bson:",inline"`
+	ArchivedAt time.Time `json:"archivedAt"`
+}
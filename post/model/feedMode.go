@@ -0,0 +1,21 @@
+package model
+
+import "strings"
+
+type FeedMode int
+
+const (
+	CHRONOLOGICAL FeedMode = iota
+	RANKED
+)
+
+// GetFeedMode reads the mode of the home feed, chronological when none is given; ok is false for unknown modes.
+func GetFeedMode(feedMode string) (mode FeedMode, ok bool) {
+	switch strings.ToLower(feedMode) {
+	case "", "chronological":
+		return CHRONOLOGICAL, true
+	case "ranked":
+		return RANKED, true
+	}
+	return CHRONOLOGICAL, false
+}
@@ -1,5 +1,50 @@
 package model
 
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
 type HashTag struct {
-	Name string `json:"name"`
+	Name       string    `bson:"_id" json:"name"`
+	PostCount  int       `json:"postCount"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+}
+
+// ParseHashTags collects the normalized hashtags of a post: every #word of the description and every word of the hashtags field.
+func ParseHashTags(description string, hashTags string) []string {
+	tags := make([]string, 0)
+	seen := make(map[string]bool)
+	add := func(value string) {
+		tag := NormalizeHashTag(value)
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	for _, word := range strings.Fields(description) {
+		for i, part := range strings.Split(word, "#") {
+			if i > 0 {
+				add(part)
+			}
+		}
+	}
+	for _, word := range strings.FieldsFunc(hashTags, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ',' || r == '#'
+	}) {
+		add(word)
+	}
+	return tags
+}
+
+func NormalizeHashTag(value string) string {
+	value = strings.TrimLeft(strings.TrimSpace(value), "#")
+	end := strings.IndexFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+	if end >= 0 {
+		value = value[:end]
+	}
+	return strings.ToLower(value)
 }
@@ -0,0 +1,15 @@
+package model
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type Highlight struct {
+	ID         primitive.ObjectID   `bson:"_id" json:"id,omitempty"`
+	ProfileID  uint                 `json:"profileId"`
+	Title      string               `json:"title"`
+	CoverMedia string               `json:"coverMedia"`
+	StoryIDs   []primitive.ObjectID `json:"storyIds"`
+	CreatedAt  time.Time            `json:"createdAt"`
+}
@@ -1,5 +1,20 @@
 package model
 
 type Location struct {
-	Name string `json:"name"`
+	Name  string   `json:"name"`
+	Point GeoPoint `json:"point"`
+}
+
+// GeoPoint is a GeoJSON point, its coordinates are longitude and latitude in that order.
+type GeoPoint struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+func NewGeoPoint(latitude float64, longitude float64) GeoPoint {
+	return GeoPoint{Type: "Point", Coordinates: []float64{longitude, latitude}}
+}
+
+func IsValidCoordinate(latitude float64, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
 }
@@ -1,9 +1,61 @@
 package model
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"strings"
+)
+
+const MaxAltTextLength = 500
 
 type Media struct {
 	ID    primitive.ObjectID 	 `bson:"_id" json:"id,omitempty"`
 	FilePath string `json:"filePath"`
 	WebSite  string `json:"webSite"`
+	ContentType string      `json:"contentType"`
+	Size        int64       `json:"size"`
+	Width       int         `json:"width"`
+	Height      int         `json:"height"`
+	Thumbnails  []Thumbnail `json:"thumbnails"`
+	Position    int         `json:"position"`
+	Kind        MediaKind   `json:"kind"`
+	AltText     string      `json:"altText"`
+}
+
+type Thumbnail struct {
+	Name     string `json:"name"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	FilePath string `json:"filePath"`
+}
+
+type MediaKind int
+
+const (
+	IMAGE MediaKind = iota
+	VIDEO
+)
+
+func GetMediaKind(contentType string) MediaKind {
+	if strings.HasPrefix(contentType, "video/") {
+		return VIDEO
+	}
+	return IMAGE
+}
+
+func (e MediaKind) ToString() string {
+	switch e {
+	case VIDEO:
+		return "VIDEO"
+	default:
+		return "IMAGE"
+	}
+}
+
+// FilePaths returns the stored original together with all of its thumbnails.
+func (media Media) FilePaths() []string {
+	ret := []string{media.FilePath}
+	for _, thumbnail := range media.Thumbnails {
+		ret = append(ret, thumbnail.FilePath)
+	}
+	return ret
 }
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"encoding/base64"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultPageSize = 20
+const MaxPageSize = 100
+
+type Page struct {
+	Cursor *Cursor
+	Limit  int
+}
+
+// Cursor points at the last post of a page; feeds are ordered by publish date and then by ID, both descending.
+type Cursor struct {
+	PublishDate time.Time
+	ID          primitive.ObjectID
+}
+
+func (page Page) IsFirst() bool {
+	return page.Cursor == nil
+}
+
+func (cursor Cursor) Encode() string {
+	value := strconv.FormatInt(cursor.PublishDate.UnixNano()/int64(time.Millisecond), 10) + ":" + cursor.ID.Hex()
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
+
+func DecodeCursor(value string) (*Cursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	parts := strings.Split(string(decoded), ":")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	millis, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	id, err := primitive.ObjectIDFromHex(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &Cursor{PublishDate: time.Unix(0, millis*int64(time.Millisecond)), ID: id}, nil
+}
@@ -17,12 +17,51 @@ type Post struct {
 	IsCampaign         bool      `json:"isCampaign"`
 	IsCloseFriendsOnly bool      `json:"isCloseFriendsOnly"`
 	Location           string    `json:"location"`
+	GeoLocation        *Location `bson:",omitempty" json:"geoLocation,omitempty"`
 	HashTags           string    `json:"hashTags"`
-	TaggedUsers        []string  `json:"taggedUsers"`
+	Tags               []string  `json:"tags"`
+	UserTags           []UserTag `json:"userTags"`
 	IsPrivate          bool      `json:"isPrivate"`
 	IsDeleted          bool      `json:"isDeleted"`
+	ExpiresAt          time.Time `json:"expiresAt"`
+	EditedAt           time.Time `json:"editedAt"`
+	Status             PostStatus `json:"status"`
+	ScheduledAt        time.Time `json:"scheduledAt"`
+	DeletedAt          time.Time `json:"deletedAt"`
+	DeletedBy          uint      `json:"deletedBy"`
+	SensitiveCategory  SensitiveCategory `json:"sensitiveCategory"`
+	SensitiveMarkedBy  uint      `json:"sensitiveMarkedBy"`
 }
 
+const StoryDuration = 24 * time.Hour
+
 func (post *Post) AddMedia(item Media) {
+	item.Position = len(post.Medias)
 	post.Medias = append(post.Medias, item)
 }
+
+// SetMedias replaces the slides of the post, numbering them in the given order.
+func (post *Post) SetMedias(medias []Media) {
+	post.Medias = make([]Media, 0)
+	for _, media := range medias {
+		post.AddMedia(media)
+	}
+}
+
+func (post *Post) IsPublished() bool {
+	return post.Status == PUBLISHED
+}
+
+func (post *Post) IsSensitive() bool {
+	return post.SensitiveCategory != NOT_SENSITIVE
+}
+
+// IsMarkedSensitiveByModerator tells whether a moderator rather than the publisher flagged the post; the publisher
+// can not clear such a flag.
+func (post *Post) IsMarkedSensitiveByModerator() bool {
+	return post.IsSensitive() && post.SensitiveMarkedBy != post.PublisherId
+}
+
+func (post *Post) IsEdited() bool {
+	return !post.EditedAt.IsZero()
+}
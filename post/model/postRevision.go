@@ -0,0 +1,23 @@
+package model
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type PostRevision struct {
+	ID          primitive.ObjectID `bson:"_id" json:"id,omitempty"`
+	PostID      primitive.ObjectID `json:"postId"`
+	Description string             `json:"description"`
+	HashTags    string             `json:"hashTags"`
+	Location    string             `json:"location"`
+	UserTags    []UserTag          `json:"userTags"`
+	EditorID    uint               `json:"editorId"`
+	EditedAt    time.Time          `json:"editedAt"`
+}
+
+func NewPostRevision(post Post, editorID uint, editedAt time.Time) PostRevision {
+	return PostRevision{ID: primitive.NewObjectID(), PostID: post.ID, Description: post.Description,
+		HashTags: post.HashTags, Location: post.Location, UserTags: post.UserTags,
+		EditorID: editorID, EditedAt: editedAt}
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"strings"
+)
+
+// PostStatus starts with PUBLISHED so that posts stored before drafts existed read as published.
+type PostStatus int
+
+const (
+	PUBLISHED PostStatus = iota
+	DRAFT
+	SCHEDULED
+)
+
+func GetPostStatus(postStatus string) PostStatus {
+	if strings.ToLower(postStatus) == "scheduled" {
+		return SCHEDULED
+	}
+	if strings.ToLower(postStatus) == "published" {
+		return PUBLISHED
+	}
+	return DRAFT
+}
+
+func (e PostStatus) ToString() string {
+	switch e {
+	case DRAFT:
+		return "DRAFT"
+	case SCHEDULED:
+		return "SCHEDULED"
+	default:
+		return "PUBLISHED"
+	}
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"encoding/base64"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type RankedPage struct {
+	Cursor *RankedCursor
+	Limit  int
+}
+
+// RankedCursor pins the time a ranked feed was computed at, so later pages rank the same candidates, and points at
+// the last post of a page; ranked feeds are ordered by score and then by ID, both descending.
+type RankedCursor struct {
+	RankedAt time.Time
+	Score    float64
+	ID       primitive.ObjectID
+}
+
+func (page RankedPage) IsFirst() bool {
+	return page.Cursor == nil
+}
+
+// IsAfter reports whether a post with the score and ID comes after the cursor in a ranked feed.
+func (cursor RankedCursor) IsAfter(score float64, id primitive.ObjectID) bool {
+	if score != cursor.Score {
+		return score < cursor.Score
+	}
+	return id.Hex() < cursor.ID.Hex()
+}
+
+func (cursor RankedCursor) Encode() string {
+	value := strconv.FormatInt(cursor.RankedAt.UnixNano()/int64(time.Millisecond), 10) + ":" +
+		strconv.FormatFloat(cursor.Score, 'g', -1, 64) + ":" + cursor.ID.Hex()
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
+
+func DecodeRankedCursor(value string) (*RankedCursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	parts := strings.Split(string(decoded), ":")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	millis, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	score, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	id, err := primitive.ObjectIDFromHex(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &RankedCursor{RankedAt: time.Unix(0, millis*int64(time.Millisecond)), Score: score, ID: id}, nil
+}
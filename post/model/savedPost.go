@@ -0,0 +1,23 @@
+package model
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// SavedPost bookmarks a post for a profile; a nil collection ID stands for the default collection.
+type SavedPost struct {
+	ID           primitive.ObjectID `bson:"_id" json:"id,omitempty"`
+	ProfileID    uint               `json:"profileId"`
+	PostID       primitive.ObjectID `json:"postId"`
+	CollectionID primitive.ObjectID `json:"collectionId"`
+	SavedAt      time.Time          `json:"savedAt"`
+}
+
+// BookmarkCollection is a named, private group of saved posts.
+type BookmarkCollection struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id,omitempty"`
+	ProfileID uint               `json:"profileId"`
+	Name      string             `json:"name"`
+	CreatedAt time.Time          `json:"createdAt"`
+}
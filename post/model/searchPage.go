@@ -0,0 +1,57 @@
+package model
+
+import (
+	"encoding/base64"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const MaxSearchQueryLength = 200
+
+type SearchPage struct {
+	Cursor *SearchCursor
+	Limit  int
+}
+
+// SearchCursor points at the last post of a search page; results are ordered by relevance, then by publish date and ID.
+type SearchCursor struct {
+	Score       float64
+	PublishDate time.Time
+	ID          primitive.ObjectID
+}
+
+func (cursor SearchCursor) Encode() string {
+	value := strconv.FormatFloat(cursor.Score, 'g', -1, 64) + ":" +
+		strconv.FormatInt(cursor.PublishDate.UnixNano()/int64(time.Millisecond), 10) + ":" + cursor.ID.Hex()
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
+
+func DecodeSearchCursor(value string) (*SearchCursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	parts := strings.Split(string(decoded), ":")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	score, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	millis, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	id, err := primitive.ObjectIDFromHex(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &SearchCursor{Score: score, PublishDate: time.Unix(0, millis*int64(time.Millisecond)), ID: id}, nil
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"strings"
+)
+
+// SensitiveCategory starts with NOT_SENSITIVE so that posts stored before the flag existed read as not sensitive.
+type SensitiveCategory int
+
+const (
+	NOT_SENSITIVE SensitiveCategory = iota
+	VIOLENCE
+	NUDITY
+	SELF_HARM
+	DISTURBING
+)
+
+// GetSensitiveCategory reads a category by name; an empty name clears the flag.
+func GetSensitiveCategory(category string) (SensitiveCategory, bool) {
+	switch strings.ToLower(category) {
+	case "", "none":
+		return NOT_SENSITIVE, true
+	case "violence":
+		return VIOLENCE, true
+	case "nudity":
+		return NUDITY, true
+	case "self_harm":
+		return SELF_HARM, true
+	case "disturbing":
+		return DISTURBING, true
+	}
+	return NOT_SENSITIVE, false
+}
+
+func (e SensitiveCategory) ToString() string {
+	switch e {
+	case VIOLENCE:
+		return "VIOLENCE"
+	case NUDITY:
+		return "NUDITY"
+	case SELF_HARM:
+		return "SELF_HARM"
+	case DISTURBING:
+		return "DISTURBING"
+	default:
+		return "NONE"
+	}
+}
@@ -0,0 +1,15 @@
+package model
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// StoryView records the first time a profile watched a story; it expires together with the story.
+type StoryView struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id,omitempty"`
+	StoryID   primitive.ObjectID `json:"storyId"`
+	ViewerID  uint               `json:"viewerId"`
+	ViewedAt  time.Time          `json:"viewedAt"`
+	ExpiresAt time.Time          `json:"expiresAt"`
+}
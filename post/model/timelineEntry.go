@@ -0,0 +1,55 @@
+package model
+
+import (
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimelineEntry is a post pushed to the cached home feed of a follower. It keeps the publisher, so the posts of a
+// profile can be taken out of a timeline when the follower stops following it.
+type TimelineEntry struct {
+	PostID      primitive.ObjectID
+	PublisherID uint
+	PublishDate time.Time
+}
+
+func NewTimelineEntry(post Post) TimelineEntry {
+	return TimelineEntry{PostID: post.ID, PublisherID: post.PublisherId, PublishDate: post.PublishDate}
+}
+
+// Member is the value the entry is stored under; its score is the publish date in milliseconds.
+func (entry TimelineEntry) Member() string {
+	return TimelineMemberPrefix(entry.PublisherID) + entry.PostID.Hex()
+}
+
+func (entry TimelineEntry) Score() float64 {
+	return float64(TimelineScore(entry.PublishDate))
+}
+
+func TimelineMemberPrefix(publisherID uint) string {
+	return strconv.FormatUint(uint64(publisherID), 10) + ":"
+}
+
+func TimelineScore(publishDate time.Time) int64 {
+	return publishDate.UnixNano() / int64(time.Millisecond)
+}
+
+func ParseTimelineEntry(member string, score float64) (TimelineEntry, error) {
+	parts := strings.Split(member, ":")
+	if len(parts) != 2 {
+		return TimelineEntry{}, fmt.Errorf("invalid timeline entry %s", member)
+	}
+	publisherID, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return TimelineEntry{}, fmt.Errorf("invalid timeline entry %s", member)
+	}
+	postID, err := primitive.ObjectIDFromHex(parts[1])
+	if err != nil {
+		return TimelineEntry{}, fmt.Errorf("invalid timeline entry %s", member)
+	}
+	return TimelineEntry{PostID: postID, PublisherID: uint(publisherID),
+		PublishDate: time.Unix(0, int64(score)*int64(time.Millisecond))}, nil
+}
@@ -0,0 +1,17 @@
+package model
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// UserTag marks a profile on a post. A tag placed on a media item carries its position as a fraction of the media
+// width and height; mentions in the description are tags without a media item.
+type UserTag struct {
+	ProfileID uint                `json:"profileId"`
+	MediaID   *primitive.ObjectID `bson:",omitempty" json:"mediaId,omitempty"`
+	X         float64             `json:"x"`
+	Y         float64             `json:"y"`
+	Approved  bool                `json:"approved"`
+}
+
+func IsValidTagPosition(x float64, y float64) bool {
+	return x >= 0 && x <= 1 && y >= 0 && y <= 1
+}
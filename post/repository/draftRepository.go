@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nistagram/post/model"
+	"nistagram/util"
+	"time"
+)
+
+func (repo *PostRepository) GetDrafts(ctx context.Context, publisherId uint, page model.Page) ([]model.Post, string, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetDrafts-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	conditions := bson.A{
+		bson.D{{"isdeleted", false}},
+		bson.D{{"publisherid", publisherId}},
+		bson.D{{"status", bson.D{{"$in", bson.A{model.DRAFT, model.SCHEDULED}}}}},
+	}
+
+	return repo.findPage(nextCtx, conditions, page)
+}
+
+// UpdateDraft saves an edited draft, unless the publisher has published it in the meantime.
+func (repo *PostRepository) UpdateDraft(ctx context.Context, post model.Post) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "UpdateDraft-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	collection := repo.getCollection()
+	filter := bson.D{{"_id", post.ID}, {"isdeleted", false},
+		{"status", bson.D{{"$in", bson.A{model.DRAFT, model.SCHEDULED}}}}}
+	update := bson.D{
+		{"$set", bson.D{
+			{"description", post.Description},
+			{"hashtags", post.HashTags},
+			{"tags", post.Tags},
+			{"location", post.Location},
+			{"geolocation", post.GeoLocation},
+			{"usertags", post.UserTags},
+			{"ishighlighted", post.IsHighlighted},
+			{"isclosefriendsonly", post.IsCloseFriendsOnly},
+			{"sensitivecategory", post.SensitiveCategory},
+			{"sensitivemarkedby", post.SensitiveMarkedBy},
+			{"status", post.Status},
+			{"scheduledat", post.ScheduledAt},
+			{"publishdate", post.PublishDate},
+			{"expiresat", post.ExpiresAt},
+		}},
+	}
+
+	result, err := collection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// PublishDueScheduledPost publishes one scheduled post whose time has come and returns it,
+// or mongo.ErrNoDocuments once none are left. Claiming a single post per call keeps several
+// post service instances from publishing the same post twice.
+func (repo *PostRepository) PublishDueScheduledPost(ctx context.Context, now time.Time) (model.Post, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "PublishDueScheduledPost-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	collection := repo.getCollection()
+	filter := bson.D{{"status", model.SCHEDULED}, {"isdeleted", false}, {"scheduledat", bson.D{{"$lte", now}}}}
+	update := mongo.Pipeline{
+		{{"$set", bson.D{
+			{"status", model.PUBLISHED},
+			{"publishdate", now},
+			{"expiresat", bson.D{{"$cond", bson.A{
+				bson.D{{"$eq", bson.A{"$posttype", model.STORY}}},
+				now.Add(model.StoryDuration),
+				"$expiresat",
+			}}}},
+		}}},
+	}
+	findOptions := options.FindOneAndUpdate().SetReturnDocument(options.After).SetSort(bson.D{{"scheduledat", 1}})
+
+	var post model.Post
+	err := collection.FindOneAndUpdate(context.TODO(), filter, update, findOptions).Decode(&post)
+	if err != nil && err != mongo.ErrNoDocuments {
+		util.Tracer.LogError(span, err)
+	}
+	return post, err
+}
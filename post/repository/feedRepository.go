@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nistagram/post/model"
+	"nistagram/post/visibility"
+	"nistagram/util"
+	"time"
+)
+
+// GetHomePageCandidates returns the newest home feed posts published in the given window, the ones the ranked feed
+// scores.
+func (repo *PostRepository) GetHomePageCandidates(ctx context.Context, policy visibility.Policy, followedIDs []uint,
+	publishedAfter time.Time, publishedBefore time.Time, limit int) ([]model.Post, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetHomePageCandidates-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	conditions := append(homePageFilter(policy, followedIDs),
+		bson.D{{"publishdate", bson.D{{"$gt", publishedAfter}, {"$lte", publishedBefore}}}})
+	findOptions := options.Find().
+		SetSort(bson.D{{"publishdate", -1}, {"_id", -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := repo.getCollection().Find(context.TODO(), bson.D{{"$and", conditions}}, findOptions)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	posts := make([]model.Post, 0)
+	if err = cursor.All(context.TODO(), &posts); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	return posts, nil
+}
+
+// GetPublisherIDs returns the publisher of each of the posts that still exist, by post ID.
+func (repo *PostRepository) GetPublisherIDs(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID]uint, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetPublisherIDs-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	ret := make(map[primitive.ObjectID]uint)
+	if len(ids) == 0 {
+		return ret, nil
+	}
+	filter := bson.D{{"_id", bson.D{{"$in", ids}}}, {"isdeleted", false}}
+	findOptions := options.Find().SetProjection(bson.D{{"publisherid", 1}})
+
+	cursor, err := repo.getCollection().Find(context.TODO(), filter, findOptions)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	var posts []struct {
+		ID          primitive.ObjectID `bson:"_id"`
+		PublisherID uint               `bson:"publisherid"`
+	}
+	if err = cursor.All(context.TODO(), &posts); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	for _, post := range posts {
+		ret[post.ID] = post.PublisherID
+	}
+	return ret, nil
+}
+
+// GetTimelineEntries returns the newest live posts of the publishers, the ones a timeline is built from.
+func (repo *PostRepository) GetTimelineEntries(ctx context.Context, publisherIDs []uint, limit int) ([]model.TimelineEntry, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetTimelineEntries-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	entries := make([]model.TimelineEntry, 0)
+	if len(publisherIDs) == 0 {
+		return entries, nil
+	}
+	filter := bson.D{{"$and", bson.A{
+		bson.D{{"isdeleted", false}},
+		publishedFilter(),
+		bson.D{{"publisherid", bson.D{{"$in", publisherIDs}}}},
+		notExpiredStoryFilter(),
+	}}}
+	findOptions := options.Find().
+		SetSort(bson.D{{"publishdate", -1}, {"_id", -1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.D{{"publisherid", 1}, {"publishdate", 1}})
+
+	cursor, err := repo.getCollection().Find(context.TODO(), filter, findOptions)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	posts := make([]model.Post, 0)
+	if err = cursor.All(context.TODO(), &posts); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	for _, post := range posts {
+		entries = append(entries, model.NewTimelineEntry(post))
+	}
+	return entries, nil
+}
+
+// GetTimelinePosts returns a home feed page made of the cached timeline posts and of the posts of the followed
+// celebrities, which are not cached. When the cached slice may not hold the whole timeline, oldest is its last entry
+// and the page stops there, so posts past it are not skipped.
+func (repo *PostRepository) GetTimelinePosts(ctx context.Context, policy visibility.Policy, followedIDs []uint,
+	postIDs []primitive.ObjectID, celebrityIDs []uint, oldest *model.Cursor, page model.Page) ([]model.Post, string, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetTimelinePosts-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	conditions := append(homePageFilter(policy, followedIDs), bson.D{{"$or", bson.A{
+		bson.D{{"_id", bson.D{{"$in", postIDs}}}},
+		bson.D{{"publisherid", bson.D{{"$in", celebrityIDs}}}},
+	}}})
+	if oldest != nil {
+		conditions = append(conditions, bson.D{{"$or", bson.A{
+			bson.D{{"publishdate", bson.D{{"$gt", oldest.PublishDate}}}},
+			bson.D{{"publishdate", oldest.PublishDate}, {"_id", bson.D{{"$gte", oldest.ID}}}},
+		}}})
+	}
+	posts, nextCursor, err := repo.findPage(nextCtx, conditions, page)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, "", err
+	}
+	if oldest != nil && nextCursor == "" {
+		nextCursor = oldest.Encode()
+	}
+	return posts, nextCursor, nil
+}
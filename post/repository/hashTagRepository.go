@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nistagram/post/dto"
+	"nistagram/post/model"
+	"nistagram/util"
+	"regexp"
+	"time"
+)
+
+const hashTagsCollectionName = "hashtags"
+
+// UpdateHashTagCounts adds delta to the post count of every tag, creating the tags that are used for the first time.
+func (repo *PostRepository) UpdateHashTagCounts(ctx context.Context, tags []string, delta int, usedAt time.Time) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "UpdateHashTagCounts-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	if len(tags) == 0 {
+		return nil
+	}
+	models := make([]mongo.WriteModel, 0)
+	for _, tag := range tags {
+		update := bson.D{{"$inc", bson.D{{"postcount", delta}}}}
+		if delta > 0 {
+			update = append(update, bson.E{"$max", bson.D{{"lastusedat", usedAt}}})
+		}
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.D{{"_id", tag}}).
+			SetUpdate(update).
+			SetUpsert(delta > 0))
+	}
+	_, err := repo.getHashTagsCollection().BulkWrite(context.TODO(), models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		util.Tracer.LogError(span, err)
+	}
+	return err
+}
+
+func (repo *PostRepository) GetHashTag(ctx context.Context, name string) (model.HashTag, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetHashTag-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	var result model.HashTag
+	err := repo.getHashTagsCollection().FindOne(context.TODO(), bson.D{{"_id", name}}).Decode(&result)
+	return result, err
+}
+
+func (repo *PostRepository) SuggestHashTags(ctx context.Context, prefix string, limit int) ([]model.HashTag, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "SuggestHashTags-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	filter := bson.D{
+		{"_id", primitive.Regex{Pattern: "^" + regexp.QuoteMeta(prefix)}},
+		{"postcount", bson.D{{"$gt", 0}}},
+	}
+	findOptions := options.Find().SetSort(bson.D{{"postcount", -1}, {"_id", 1}}).SetLimit(int64(limit))
+	cursor, err := repo.getHashTagsCollection().Find(context.TODO(), filter, findOptions)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	hashTags := make([]model.HashTag, 0)
+	if err = cursor.All(context.TODO(), &hashTags); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	return hashTags, nil
+}
+
+// GetTrendingHashTags compares how often each tag was used by public posts in the current window and in the window before it.
+func (repo *PostRepository) GetTrendingHashTags(ctx context.Context, window time.Duration, limit int) ([]dto.HashTagTrendDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetTrendingHashTags-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	windowStart := time.Now().Add(-window)
+	previousStart := windowStart.Add(-window)
+	isCurrent := bson.D{{"$gte", bson.A{"$publishdate", windowStart}}}
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{
+			{"isdeleted", false},
+			{"isprivate", false},
+			{"status", bson.D{{"$nin", bson.A{model.DRAFT, model.SCHEDULED}}}},
+			{"publishdate", bson.D{{"$gte", previousStart}}},
+		}}},
+		{{"$unwind", "$tags"}},
+		{{"$group", bson.D{
+			{"_id", "$tags"},
+			{"count", bson.D{{"$sum", bson.D{{"$cond", bson.A{isCurrent, 1, 0}}}}}},
+			{"previouscount", bson.D{{"$sum", bson.D{{"$cond", bson.A{isCurrent, 0, 1}}}}}},
+		}}},
+		{{"$match", bson.D{{"count", bson.D{{"$gt", 0}}}}}},
+		{{"$project", bson.D{
+			{"_id", 0},
+			{"name", "$_id"},
+			{"count", 1},
+			{"previouscount", 1},
+			{"growth", bson.D{{"$divide", bson.A{
+				bson.D{{"$subtract", bson.A{"$count", "$previouscount"}}},
+				bson.D{{"$add", bson.A{"$previouscount", 1}}},
+			}}}},
+		}}},
+		{{"$sort", bson.D{{"growth", -1}, {"count", -1}, {"name", 1}}}},
+		{{"$limit", limit}},
+	}
+
+	cursor, err := repo.getCollection().Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	trends := make([]dto.HashTagTrendDTO, 0)
+	if err = cursor.All(context.TODO(), &trends); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	return trends, nil
+}
+
+func (repo *PostRepository) GetPostsWithoutTags(ctx context.Context) ([]model.Post, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetPostsWithoutTags-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	cursor, err := repo.getCollection().Find(context.TODO(), bson.D{{"tags", bson.D{{"$exists", false}}}})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	var posts []model.Post
+	if err = cursor.All(context.TODO(), &posts); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	return posts, nil
+}
+
+func (repo *PostRepository) SetPostTags(ctx context.Context, id primitive.ObjectID, tags []string) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "SetPostTags-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	filter := bson.D{{"_id", id}}
+	update := bson.D{
+		{"$set", bson.D{
+			{"tags", tags},
+		}},
+	}
+	_, err := repo.getCollection().UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+	}
+	return err
+}
+
+func (repo *PostRepository) getHashTagsCollection() *mongo.Collection {
+	return repo.Client.Database(postDbName).Collection(hashTagsCollectionName)
+}
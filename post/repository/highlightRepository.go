@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nistagram/post/model"
+	"nistagram/util"
+)
+
+const highlightsCollectionName = "highlights"
+
+func (repo *PostRepository) CreateHighlight(ctx context.Context, highlight *model.Highlight) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "CreateHighlight-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	_, err := repo.getHighlightsCollection().InsertOne(context.TODO(), highlight)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+	}
+	return err
+}
+
+func (repo *PostRepository) ReadHighlight(ctx context.Context, id primitive.ObjectID) (model.Highlight, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "ReadHighlight-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	var result model.Highlight
+	err := repo.getHighlightsCollection().FindOne(context.TODO(), bson.D{{"_id", id}}).Decode(&result)
+	return result, err
+}
+
+func (repo *PostRepository) UpdateHighlight(ctx context.Context, highlight model.Highlight) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "UpdateHighlight-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	filter := bson.D{{"_id", highlight.ID}}
+	update := bson.D{
+		{"$set", bson.D{
+			{"title", highlight.Title},
+			{"covermedia", highlight.CoverMedia},
+			{"storyids", highlight.StoryIDs},
+		}},
+	}
+
+	result, err := repo.getHighlightsCollection().UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		util.Tracer.LogError(span, fmt.Errorf("highlight not found"))
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (repo *PostRepository) DeleteHighlight(ctx context.Context, id primitive.ObjectID) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "DeleteHighlight-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	result, err := repo.getHighlightsCollection().DeleteOne(context.TODO(), bson.D{{"_id", id}})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if result.DeletedCount == 0 {
+		util.Tracer.LogError(span, fmt.Errorf("highlight not found"))
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (repo *PostRepository) GetProfileHighlights(ctx context.Context, profileID uint) ([]model.Highlight, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetProfileHighlights-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	findOptions := options.Find().SetSort(bson.D{{"createdat", 1}})
+	cursor, err := repo.getHighlightsCollection().Find(context.TODO(), bson.D{{"profileid", profileID}}, findOptions)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	highlights := make([]model.Highlight, 0)
+	if err = cursor.All(context.TODO(), &highlights); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	return highlights, nil
+}
+
+// GetStoriesByIDs looks the stories up both among the posts and in the story archive, since highlighted stories outlive their expiry.
+func (repo *PostRepository) GetStoriesByIDs(ctx context.Context, ids []primitive.ObjectID) ([]model.Post, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetStoriesByIDs-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	stories := make([]model.Post, 0)
+	if len(ids) == 0 {
+		return stories, nil
+	}
+	filter := bson.D{{"_id", bson.D{{"$in", ids}}}, {"posttype", model.STORY}}
+	for _, collection := range []*mongo.Collection{repo.getCollection(), repo.getStoryArchiveCollection()} {
+		cursor, err := collection.Find(context.TODO(), filter)
+		if err != nil {
+			util.Tracer.LogError(span, err)
+			return nil, err
+		}
+		var result []model.Post
+		if err = cursor.All(context.TODO(), &result); err != nil {
+			util.Tracer.LogError(span, err)
+			return nil, err
+		}
+		stories = append(stories, result...)
+	}
+	return stories, nil
+}
+
+func (repo *PostRepository) getHighlightedStoryIDs(ctx context.Context) ([]interface{}, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "getHighlightedStoryIDs-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	ids, err := repo.getHighlightsCollection().Distinct(context.TODO(), "storyids", bson.D{})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	if ids == nil {
+		ids = make([]interface{}, 0)
+	}
+	return ids, nil
+}
+
+func (repo *PostRepository) getHighlightsCollection() *mongo.Collection {
+	return repo.Client.Database(postDbName).Collection(highlightsCollectionName)
+}
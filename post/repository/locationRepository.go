@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"nistagram/post/dto"
+	"nistagram/post/model"
+	"nistagram/post/visibility"
+	"nistagram/util"
+)
+
+const earthRadiusInMeters = 6378100
+
+func (repo *PostRepository) GetPublicNearby(ctx context.Context, point model.GeoPoint, radius float64, policy visibility.Policy, page model.Page) ([]model.Post, string, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetPublicNearby-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	conditions := append(publicFilter(policy), withinRadiusFilter(point, radius))
+
+	return repo.findPage(nextCtx, conditions, page)
+}
+
+func (repo *PostRepository) GetPublicByPlace(ctx context.Context, name string, policy visibility.Policy, page model.Page) ([]model.Post, string, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetPublicByPlace-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	conditions := append(publicFilter(policy), bson.D{{"geolocation.name", name}})
+
+	return repo.findPage(nextCtx, conditions, page)
+}
+
+// GetPublicPlaces groups the public posts around the point by the name of their place, most used places first.
+func (repo *PostRepository) GetPublicPlaces(ctx context.Context, point model.GeoPoint, radius float64, policy visibility.Policy, limit int) ([]dto.PlaceDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetPublicPlaces-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	conditions := append(publicFilter(policy), withinRadiusFilter(point, radius))
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{"$and", conditions}}}},
+		{{"$group", bson.D{
+			{"_id", "$geolocation.name"},
+			{"point", bson.D{{"$first", "$geolocation.point"}}},
+			{"postcount", bson.D{{"$sum", 1}}},
+		}}},
+		{{"$project", bson.D{{"_id", 0}, {"name", "$_id"}, {"point", 1}, {"postcount", 1}}}},
+		{{"$sort", bson.D{{"postcount", -1}, {"name", 1}}}},
+		{{"$limit", limit}},
+	}
+
+	cursor, err := repo.getCollection().Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	places := make([]dto.PlaceDTO, 0)
+	if err = cursor.All(context.TODO(), &places); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	return places, nil
+}
+
+func withinRadiusFilter(point model.GeoPoint, radius float64) bson.D {
+	return bson.D{{"geolocation.point", bson.D{{"$geoWithin", bson.D{
+		{"$centerSphere", bson.A{point.Coordinates, radius / earthRadiusInMeters}},
+	}}}}}
+}
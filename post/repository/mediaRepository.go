@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"nistagram/post/model"
+	"nistagram/util"
+)
+
+// UpdateMedias stores the arranged slides of a post together with the tags that are left on them.
+func (repo *PostRepository) UpdateMedias(ctx context.Context, id primitive.ObjectID, medias []model.Media, userTags []model.UserTag) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "UpdateMedias-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	filter := bson.D{{"_id", id}, {"isdeleted", false}}
+	update := bson.D{{"$set", bson.D{
+		{"medias", medias},
+		{"usertags", userTags},
+	}}}
+
+	result, err := repo.getCollection().UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// BackfillMedias numbers the slides of posts created before media positions were stored, in the order they were
+// uploaded, and sets their kind from their content type.
+func (repo *PostRepository) BackfillMedias(ctx context.Context) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "BackfillMedias-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	filter := bson.D{{"medias", bson.D{{"$elemMatch", bson.D{{"position", bson.D{{"$exists", false}}}}}}}}
+	update := mongo.Pipeline{
+		{{"$set", bson.D{
+			{"medias", bson.D{{"$map", bson.D{
+				{"input", bson.D{{"$range", bson.A{0, bson.D{{"$size", "$medias"}}}}}},
+				{"as", "position"},
+				{"in", bson.D{{"$let", bson.D{
+					{"vars", bson.D{{"media", bson.D{{"$arrayElemAt", bson.A{"$medias", "$$position"}}}}}},
+					{"in", bson.D{{"$mergeObjects", bson.A{"$$media", bson.D{
+						{"position", "$$position"},
+						{"kind", bson.D{{"$cond", bson.A{
+							bson.D{{"$eq", bson.A{bson.D{{"$substrBytes", bson.A{"$$media.contenttype", 0, 6}}}, "video/"}}},
+							model.VIDEO, model.IMAGE,
+						}}}},
+					}}}}},
+				}}}},
+			}}}},
+		}}},
+	}
+
+	_, err := repo.getCollection().UpdateMany(context.TODO(), filter, update)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+	}
+	return err
+}
@@ -6,106 +6,92 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
-	"nistagram/post/dto"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"nistagram/post/model"
+	"nistagram/post/visibility"
 	"nistagram/util"
-	"strings"
+	"regexp"
 	"time"
 )
 
 const postsCollectionName = "posts"
+const storyArchiveCollectionName = "storyarchive"
 const postDbName = "postdb"
 
 type PostRepository struct {
 	Client *mongo.Client
 }
 
-func (repo *PostRepository) GetProfilesPosts(ctx context.Context, followingProfiles []util.FollowingProfileDTO, targetUsername string) ([]model.Post, error) {
+func (repo *PostRepository) GetProfilesPosts(ctx context.Context, policy visibility.Policy, targetUsername string, page model.Page) ([]model.Post, string, error) {
 	span := util.Tracer.StartSpanFromContext(ctx, "GetProfilesPosts-repository")
 	defer util.Tracer.FinishSpan(span)
 
-	collection := repo.getCollection()
-
-	filter := bson.D{{"isdeleted", false}, {"publisherusername", targetUsername}}
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
 
-	cursor, err := collection.Find(context.TODO(), filter)
-	if err != nil {
-		util.Tracer.LogError(span, err)
-		return nil, err
+	conditions := bson.A{
+		policy.Filter(),
+		publishedFilter(),
+		bson.D{{"publisherusername", targetUsername}},
 	}
 
-	var posts []model.Post
+	return repo.findPage(nextCtx, conditions, page)
+}
 
-	for cursor.Next(context.TODO()) {
-		var result model.Post
-		err = cursor.Decode(&result)
-		if result.IsPrivate == false || util.IsFollowed(followingProfiles, result.PublisherId) {
-			if result.PostType == model.GetPostType("story") {
-				if result.IsCloseFriendsOnly && !util.IsCloseFriend(followingProfiles, result.PublisherId){
-					continue
-				}
-				duration, err := time.ParseDuration("24h")
-				if err != nil {
-					util.Tracer.LogError(span, err)
-					return nil, err
-				}
-				if result.IsHighlighted || time.Now().Before(result.PublishDate.Add(duration)) {
-					posts = append(posts, result)
-				}
-			} else {
-				posts = append(posts, result)
-			}
-		}
-	}
+func (repo *PostRepository) GetPublic(ctx context.Context, policy visibility.Policy, page model.Page) ([]model.Post, string, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetPublic-repository")
+	defer util.Tracer.FinishSpan(span)
 
-	return posts, nil
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	return repo.findPage(nextCtx, publicFilter(policy), page)
 }
 
-func (repo *PostRepository) GetPublic(ctx context.Context, blockedRelationships []uint) ([]model.Post, error) {
-	span := util.Tracer.StartSpanFromContext(ctx, "GetPublic-repository")
+func (repo *PostRepository) GetPublicPostByLocation(ctx context.Context, location string, policy visibility.Policy, page model.Page) ([]model.Post, string, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetPublicPostByLocation-repository")
 	defer util.Tracer.FinishSpan(span)
 
-	collection := repo.getCollection()
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
 
-	filter := bson.D{{"isdeleted", false}, {"isprivate", false}}
+	conditions := append(publicFilter(policy),
+		bson.D{{"location", primitive.Regex{Pattern: regexp.QuoteMeta(location)}}})
 
-	cursor, err := collection.Find(context.TODO(), filter)
-	if err != nil {
-		util.Tracer.LogError(span, err)
-		return nil, err
-	}
+	return repo.findPage(nextCtx, conditions, page)
+}
 
-	var posts []model.Post
+func (repo *PostRepository) GetPublicPostByHashTag(ctx context.Context, hashTag string, policy visibility.Policy, page model.Page) ([]model.Post, string, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetPublicPostByHashTag-repository")
+	defer util.Tracer.FinishSpan(span)
 
-	for cursor.Next(context.TODO()) {
-		var result model.Post
-		err = cursor.Decode(&result)
-		if !util.Contains(blockedRelationships, result.PublisherId) {
-			if result.PostType == model.GetPostType("story") {
-				duration, err := time.ParseDuration("24h")
-				if err != nil {
-					util.Tracer.LogError(span, err)
-					return nil, err
-				}
-				if time.Now().Before(result.PublishDate.Add(duration)) {
-					posts = append(posts, result)
-				}
-			} else {
-				posts = append(posts, result)
-			}
-		}
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	conditions := append(publicFilter(policy),
+		bson.D{{"tags", model.NormalizeHashTag(hashTag)}})
+
+	return repo.findPage(nextCtx, conditions, page)
+}
+
+func (repo *PostRepository) GetMyPosts(ctx context.Context, loggedUserId uint, page model.Page) ([]model.Post, string, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetMyPosts-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	conditions := bson.A{
+		bson.D{{"isdeleted", false}},
+		publishedFilter(),
+		bson.D{{"publisherid", loggedUserId}},
 	}
 
-	return posts, nil
+	return repo.findPage(nextCtx, conditions, page)
 }
 
-func (repo *PostRepository) GetPublicPostByLocation(ctx context.Context, location string, blockedRelationships []uint) ([]model.Post, error) {
-	span := util.Tracer.StartSpanFromContext(ctx, "GetPublicPostByLocation-repository")
+func (repo *PostRepository) GetAllPublisherPosts(ctx context.Context, publisherId uint) ([]model.Post, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetAllPublisherPosts-repository")
 	defer util.Tracer.FinishSpan(span)
 
 	collection := repo.getCollection()
 
-	filter := bson.D{{"isdeleted", false}, {"isprivate", false}}
+	filter := bson.D{{"isdeleted", false}, {"publisherid", publisherId}}
 
 	cursor, err := collection.Find(context.TODO(), filter)
 	if err != nil {
@@ -114,137 +100,130 @@ func (repo *PostRepository) GetPublicPostByLocation(ctx context.Context, locatio
 	}
 
 	var posts []model.Post
-
-	for cursor.Next(context.TODO()) {
-		var result model.Post
-		err = cursor.Decode(&result)
-		if !util.Contains(blockedRelationships, result.PublisherId) {
-			if strings.Contains(result.Location, location) {
-				if result.PostType == model.GetPostType("story") {
-					duration, err := time.ParseDuration("24h")
-					if err != nil {
-						util.Tracer.LogError(span, err)
-						return nil, err
-					}
-					if time.Now().Before(result.PublishDate.Add(duration)) {
-						posts = append(posts, result)
-					}
-				} else {
-					posts = append(posts, result)
-				}
-			}
-		}
+	if err = cursor.All(context.TODO(), &posts); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
 	}
 
 	return posts, nil
 }
 
-func (repo *PostRepository) GetPublicPostByHashTag(ctx context.Context, hashTag string, blockedRelationships []uint) ([]model.Post, error) {
-	span := util.Tracer.StartSpanFromContext(ctx, "GetPublicPostByHashTag-repository")
+func (repo *PostRepository) GetStoryArchive(ctx context.Context, publisherId uint, page model.Page) ([]model.Post, string, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetStoryArchive-repository")
 	defer util.Tracer.FinishSpan(span)
 
-	collection := repo.getCollection()
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
 
-	filter := bson.D{{"isdeleted", false}, {"isprivate", false}}
-
-	cursor, err := collection.Find(context.TODO(), filter)
-	if err != nil {
-		util.Tracer.LogError(span, err)
-		return nil, err
+	conditions := bson.A{
+		bson.D{{"publisherid", publisherId}},
 	}
 
-	var posts []model.Post
+	return repo.findPageIn(nextCtx, repo.getStoryArchiveCollection(), conditions, page)
+}
 
-	for cursor.Next(context.TODO()) {
-		var result model.Post
-		err = cursor.Decode(&result)
-		if !util.Contains(blockedRelationships, result.PublisherId) {
-			if strings.Contains(result.HashTags, hashTag) {
-				if result.PostType == model.GetPostType("story") {
-					duration, err := time.ParseDuration("24h")
-					if err != nil {
-						util.Tracer.LogError(span, err)
-						return nil, err
-					}
-					if time.Now().Before(result.PublishDate.Add(duration)) {
-						posts = append(posts, result)
-					}
-				} else {
-					posts = append(posts, result)
-				}
-			}
-		}
+// BackfillStoryExpiry sets the expiry of stories created before it was stored on the document.
+func (repo *PostRepository) BackfillStoryExpiry(ctx context.Context) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "BackfillStoryExpiry-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	collection := repo.getCollection()
+	filter := bson.D{{"posttype", model.STORY}, {"expiresat", bson.D{{"$exists", false}}}}
+	update := mongo.Pipeline{
+		{{"$set", bson.D{
+			{"expiresat", bson.D{{"$add", bson.A{"$publishdate", int64(model.StoryDuration / time.Millisecond)}}}},
+		}}},
 	}
 
-	return posts, nil
+	_, err := collection.UpdateMany(context.TODO(), filter, update)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+	}
+	return err
 }
 
-func (repo *PostRepository) GetMyPosts(ctx context.Context, loggedUserId uint) ([]model.Post, error) {
-	span := util.Tracer.StartSpanFromContext(ctx, "GetMyPosts-repository")
+// ArchiveExpiredStories moves expired stories that are not highlighted from the posts collection to the story archive.
+// Stories turned into campaigns stay, as sponsored posts are read from the posts collection while the campaign runs.
+func (repo *PostRepository) ArchiveExpiredStories(ctx context.Context, now time.Time) (int, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "ArchiveExpiredStories-repository")
 	defer util.Tracer.FinishSpan(span)
 
 	collection := repo.getCollection()
-
-	filter := bson.D{{"isdeleted", false}, {"publisherid", loggedUserId}}
+	filter := bson.D{{"posttype", model.STORY}, {"isdeleted", false}, {"ishighlighted", false}, {"iscampaign", false},
+		{"status", bson.D{{"$nin", bson.A{model.DRAFT, model.SCHEDULED}}}}, {"expiresat", bson.D{{"$lte", now}}}}
 
 	cursor, err := collection.Find(context.TODO(), filter)
 	if err != nil {
 		util.Tracer.LogError(span, err)
-		return nil, err
+		return 0, err
 	}
-
-	var posts []model.Post
-
-	for cursor.Next(context.TODO()) {
-		var result model.Post
-		err = cursor.Decode(&result)
-		if err != nil{
-			util.Tracer.LogError(span, err)
-		}
-		posts = append(posts, result)
+	var stories []model.Post
+	if err = cursor.All(context.TODO(), &stories); err != nil {
+		util.Tracer.LogError(span, err)
+		return 0, err
+	}
+	if len(stories) == 0 {
+		return 0, nil
 	}
 
-	return posts, nil
+	// Stories are upserted by ID, so a story archived by a run that failed before deleting it is archived again
+	// instead of stopping every later run on a duplicate key.
+	archived := make([]mongo.WriteModel, 0)
+	ids := make([]primitive.ObjectID, 0)
+	for _, story := range stories {
+		archived = append(archived, mongo.NewReplaceOneModel().SetFilter(bson.D{{"_id", story.ID}}).
+			SetReplacement(model.ArchivedStory{Post: story, ArchivedAt: now}).SetUpsert(true))
+		ids = append(ids, story.ID)
+	}
+	if _, err = repo.getStoryArchiveCollection().BulkWrite(context.TODO(), archived); err != nil {
+		util.Tracer.LogError(span, err)
+		return 0, err
+	}
+	if _, err = collection.DeleteMany(context.TODO(), bson.D{{"_id", bson.D{{"$in", ids}}}}); err != nil {
+		util.Tracer.LogError(span, err)
+		return 0, err
+	}
+	return len(stories), nil
 }
 
-func (repo *PostRepository) GetPostsForHomePage(ctx context.Context, followingProfiles []util.FollowingProfileDTO) ([]model.Post, error) {
-	span := util.Tracer.StartSpanFromContext(ctx, "GetPostsForHomePage-repository")
+// PurgeArchivedStories deletes stories archived before the given time, except the ones kept in highlights, and returns them so their media can be removed.
+func (repo *PostRepository) PurgeArchivedStories(ctx context.Context, archivedBefore time.Time) ([]model.ArchivedStory, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "PurgeArchivedStories-repository")
 	defer util.Tracer.FinishSpan(span)
 
-	collection := repo.getCollection()
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
 
-	cursor, err := collection.Find(context.TODO(), bson.D{})
+	highlightedIDs, err := repo.getHighlightedStoryIDs(nextCtx)
 	if err != nil {
 		util.Tracer.LogError(span, err)
 		return nil, err
 	}
 
-	var posts []model.Post
+	collection := repo.getStoryArchiveCollection()
+	filter := bson.D{{"archivedat", bson.D{{"$lt", archivedBefore}}}, {"_id", bson.D{{"$nin", highlightedIDs}}}}
 
-	for cursor.Next(context.TODO()) {
-		var result model.Post
-		err = cursor.Decode(&result)
-		if util.IsFollowed(followingProfiles, result.PublisherId) && !result.IsDeleted {
-			if result.PostType == model.GetPostType("story") {
-				if result.IsCloseFriendsOnly && !util.IsCloseFriend(followingProfiles, result.PublisherId) {
-					continue
-				}
-
-				duration, err := time.ParseDuration("24h")
-				if err != nil {
-					util.Tracer.LogError(span, err)
-					return nil, err
-				}
-				if time.Now().Before(result.PublishDate.Add(duration)) {
-					posts = append(posts, result)
-				}
-			} else {
-				posts = append(posts, result)
-			}
-		}
+	cursor, err := collection.Find(context.TODO(), filter)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	var stories []model.ArchivedStory
+	if err = cursor.All(context.TODO(), &stories); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	if len(stories) == 0 {
+		return stories, nil
 	}
 
-	return posts, nil
+	ids := make([]primitive.ObjectID, 0)
+	for _, story := range stories {
+		ids = append(ids, story.ID)
+	}
+	if _, err = collection.DeleteMany(context.TODO(), bson.D{{"_id", bson.D{{"$in", ids}}}}); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	return stories, nil
 }
 
 func (repo *PostRepository) Create(ctx context.Context, post *model.Post) error {
@@ -267,15 +246,17 @@ func (repo *PostRepository) Read(ctx context.Context, id primitive.ObjectID) (mo
 	return result, err
 }
 
-func (repo *PostRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+func (repo *PostRepository) Delete(ctx context.Context, id primitive.ObjectID, deletedBy uint, deletedAt time.Time) error {
 	span := util.Tracer.StartSpanFromContext(ctx, "Delete-repository")
 	defer util.Tracer.FinishSpan(span)
 
 	collection := repo.getCollection()
-	filter := bson.D{{"_id", id}}
+	filter := bson.D{{"_id", id}, {"isdeleted", false}}
 	update := bson.D{
 		{"$set", bson.D{
 			{"isdeleted", true},
+			{"deletedby", deletedBy},
+			{"deletedat", deletedAt},
 		}},
 	}
 	result, _ := collection.UpdateOne(context.TODO(), filter, update)
@@ -286,16 +267,25 @@ func (repo *PostRepository) Delete(ctx context.Context, id primitive.ObjectID) e
 	return nil
 }
 
-func (repo *PostRepository) Update(ctx context.Context, id primitive.ObjectID, post dto.PostDto) error {
+func (repo *PostRepository) Update(ctx context.Context, post model.Post) error {
 	span := util.Tracer.StartSpanFromContext(ctx, "Update-repository")
 	defer util.Tracer.FinishSpan(span)
 
 	collection := repo.getCollection()
-	filter := bson.D{{"_id", id}}
+	filter := bson.D{{"_id", post.ID}}
 	update := bson.D{
 		{"$set", bson.D{
+			{"description", post.Description},
+			{"hashtags", post.HashTags},
+			{"tags", post.Tags},
+			{"location", post.Location},
+			{"geolocation", post.GeoLocation},
+			{"usertags", post.UserTags},
 			{"ishighlighted", post.IsHighlighted},
-			{"isclosefriendsonly", post.IsHighlighted},
+			{"isclosefriendsonly", post.IsCloseFriendsOnly},
+			{"sensitivecategory", post.SensitiveCategory},
+			{"sensitivemarkedby", post.SensitiveMarkedBy},
+			{"editedat", post.EditedAt},
 		}},
 	}
 
@@ -307,6 +297,28 @@ func (repo *PostRepository) Update(ctx context.Context, id primitive.ObjectID, p
 	return nil
 }
 
+func (repo *PostRepository) UpdateSensitiveCategory(ctx context.Context, id primitive.ObjectID, category model.SensitiveCategory, markedBy uint) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "UpdateSensitiveCategory-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	collection := repo.getCollection()
+	filter := bson.D{{"_id", id}, {"isdeleted", false}}
+	update := bson.D{{"$set", bson.D{
+		{"sensitivecategory", category},
+		{"sensitivemarkedby", markedBy},
+	}}}
+
+	result, err := collection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
 func (repo *PostRepository) DeleteUserPosts(ctx context.Context, id uint) error {
 	span := util.Tracer.StartSpanFromContext(ctx, "DeleteUserPosts-repository")
 	defer util.Tracer.FinishSpan(span)
@@ -332,6 +344,10 @@ func (repo *PostRepository) ChangeUsername(ctx context.Context, id uint, usernam
 		}},
 	}
 
+	if _, err := repo.getStoryArchiveCollection().UpdateMany(context.TODO(), filter, update); err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
 	return repo.updateMany(ctx, filter, update)
 }
 
@@ -345,6 +361,10 @@ func (repo *PostRepository) ChangePrivacy(ctx context.Context, id uint, isPrivat
 		}},
 	}
 
+	if _, err := repo.getStoryArchiveCollection().UpdateMany(context.TODO(), filter, update); err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
 	return repo.updateMany(ctx, filter, update)
 }
 
@@ -379,7 +399,7 @@ func (repo *PostRepository) GetMediaById(ctx context.Context, id string) (model.
 	}
 	collection := repo.getCollection()
 
-	filter := bson.D{{"medias._id", mediaId}}
+	filter := bson.D{{"medias._id", mediaId}, {"isdeleted", false}}
 
 	cursor, err := collection.Find(context.TODO(), filter)
 	if err != nil {
@@ -399,11 +419,11 @@ func (repo *PostRepository) GetMediaById(ctx context.Context, id string) (model.
 
 	for _, media := range post.Medias{
 		if media.ID == mediaId{
-			retMedia = media
+			return media, nil
 		}
 	}
 
-	return retMedia, nil
+	return retMedia, mongo.ErrNoDocuments
 }
 
 func (repo *PostRepository) updateMany(ctx context.Context, filter bson.D, update bson.D) error {
@@ -420,6 +440,93 @@ func (repo *PostRepository) updateMany(ctx context.Context, filter bson.D, updat
 	return nil
 }
 
+
+func (repo *PostRepository) findPage(ctx context.Context, conditions bson.A, page model.Page) ([]model.Post, string, error) {
+	return repo.findPageIn(ctx, repo.getCollection(), conditions, page)
+}
+
+// findPageIn runs one bounded query ordered by publish date and ID and returns the cursor of the next page, or an empty string on the last page.
+func (repo *PostRepository) findPageIn(ctx context.Context, collection *mongo.Collection, conditions bson.A, page model.Page) ([]model.Post, string, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "findPage-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	if page.Cursor != nil {
+		conditions = append(conditions, bson.D{{"$or", bson.A{
+			bson.D{{"publishdate", bson.D{{"$lt", page.Cursor.PublishDate}}}},
+			bson.D{{"publishdate", page.Cursor.PublishDate}, {"_id", bson.D{{"$lt", page.Cursor.ID}}}},
+		}}})
+	}
+	filter := bson.D{{"$and", conditions}}
+	findOptions := options.Find().
+		SetSort(bson.D{{"publishdate", -1}, {"_id", -1}}).
+		SetLimit(int64(page.Limit + 1))
+
+	cursor, err := collection.Find(context.TODO(), filter, findOptions)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, "", err
+	}
+
+	posts := make([]model.Post, 0)
+	if err = cursor.All(context.TODO(), &posts); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, "", err
+	}
+
+	if len(posts) <= page.Limit {
+		return posts, "", nil
+	}
+	posts = posts[:page.Limit]
+	last := posts[len(posts)-1]
+	return posts, model.Cursor{PublishDate: last.PublishDate, ID: last.ID}.Encode(), nil
+}
+
+// publicFilter matches the live posts of public profiles the policy lets the viewer see; sensitive posts are left
+// out unless the viewer opted in to see them.
+func publicFilter(policy visibility.Policy) bson.A {
+	conditions := bson.A{
+		policy.Filter(),
+		publishedFilter(),
+		bson.D{{"isprivate", false}},
+		notExpiredStoryFilter(),
+	}
+	if !policy.ShowsSensitiveContent() {
+		conditions = append(conditions, notSensitiveFilter())
+	}
+	return conditions
+}
+
+// homePageFilter matches the live posts of the followed profiles the policy lets the viewer see.
+func homePageFilter(policy visibility.Policy, followedIDs []uint) bson.A {
+	return bson.A{
+		policy.Filter(),
+		publishedFilter(),
+		bson.D{{"publisherid", bson.D{{"$in", followedIDs}}}},
+		notExpiredStoryFilter(),
+	}
+}
+
+// publishedFilter also matches posts stored before the status field existed.
+func publishedFilter() bson.D {
+	return bson.D{{"status", bson.D{{"$nin", bson.A{model.DRAFT, model.SCHEDULED}}}}}
+}
+
+// notSensitiveFilter also matches posts stored before the sensitive flag existed.
+func notSensitiveFilter() bson.D {
+	return bson.D{{"sensitivecategory", bson.D{{"$in", bson.A{model.NOT_SENSITIVE, nil}}}}}
+}
+
+func notExpiredStoryFilter() bson.D {
+	return bson.D{{"$or", bson.A{
+		bson.D{{"posttype", bson.D{{"$ne", model.STORY}}}},
+		bson.D{{"expiresat", bson.D{{"$gt", time.Now()}}}},
+	}}}
+}
+
 func (repo *PostRepository) getCollection() *mongo.Collection {
 	return repo.Client.Database(postDbName).Collection(postsCollectionName)
 }
+
+func (repo *PostRepository) getStoryArchiveCollection() *mongo.Collection {
+	return repo.Client.Database(postDbName).Collection(storyArchiveCollectionName)
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nistagram/post/model"
+	"nistagram/util"
+)
+
+const postRevisionsCollectionName = "postrevisions"
+
+func (repo *PostRepository) CreateRevisions(ctx context.Context, revisions []model.PostRevision) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "CreateRevisions-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	documents := make([]interface{}, 0)
+	for _, revision := range revisions {
+		documents = append(documents, revision)
+	}
+	_, err := repo.getPostRevisionsCollection().InsertMany(context.TODO(), documents)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+	}
+	return err
+}
+
+func (repo *PostRepository) GetRevisions(ctx context.Context, postID primitive.ObjectID) ([]model.PostRevision, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetRevisions-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	findOptions := options.Find().SetSort(bson.D{{"editedat", -1}})
+	cursor, err := repo.getPostRevisionsCollection().Find(context.TODO(), bson.D{{"postid", postID}}, findOptions)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	revisions := make([]model.PostRevision, 0)
+	if err = cursor.All(context.TODO(), &revisions); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (repo *PostRepository) getPostRevisionsCollection() *mongo.Collection {
+	return repo.Client.Database(postDbName).Collection(postRevisionsCollectionName)
+}
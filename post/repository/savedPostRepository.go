@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nistagram/post/model"
+	"nistagram/post/visibility"
+	"nistagram/util"
+	"time"
+)
+
+const savedPostsCollectionName = "savedposts"
+const bookmarkCollectionsCollectionName = "bookmarkcollections"
+
+// SavePost is idempotent; saving a post twice into the same collection keeps the first save.
+func (repo *PostRepository) SavePost(ctx context.Context, savedPost model.SavedPost) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "SavePost-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	filter := bson.D{{"profileid", savedPost.ProfileID}, {"postid", savedPost.PostID}, {"collectionid", savedPost.CollectionID}}
+	update := bson.D{{"$setOnInsert", bson.D{{"_id", savedPost.ID}, {"savedat", savedPost.SavedAt}}}}
+
+	_, err := repo.getSavedPostsCollection().UpdateOne(context.TODO(), filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		util.Tracer.LogError(span, err)
+	}
+	return err
+}
+
+func (repo *PostRepository) UnsavePost(ctx context.Context, profileID uint, postID primitive.ObjectID, collectionID primitive.ObjectID) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "UnsavePost-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	filter := bson.D{{"profileid", profileID}, {"postid", postID}, {"collectionid", collectionID}}
+	result, err := repo.getSavedPostsCollection().DeleteOne(context.TODO(), filter)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// GetSavedPosts pages through a collection newest save first, skipping posts the profile can no longer see.
+func (repo *PostRepository) GetSavedPosts(ctx context.Context, profileID uint, collectionID primitive.ObjectID,
+	policy visibility.Policy, page model.Page) ([]model.Post, string, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetSavedPosts-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	match := bson.D{{"profileid", profileID}, {"collectionid", collectionID}}
+	if page.Cursor != nil {
+		match = append(match, bson.E{"$or", bson.A{
+			bson.D{{"savedat", bson.D{{"$lt", page.Cursor.PublishDate}}}},
+			bson.D{{"savedat", page.Cursor.PublishDate}, {"_id", bson.D{{"$lt", page.Cursor.ID}}}},
+		}})
+	}
+	pipeline := mongo.Pipeline{
+		{{"$match", match}},
+		{{"$sort", bson.D{{"savedat", -1}, {"_id", -1}}}},
+		{{"$lookup", bson.D{{"from", postsCollectionName}, {"localField", "postid"}, {"foreignField", "_id"}, {"as", "post"}}}},
+		{{"$unwind", "$post"}},
+		{{"$match", bson.D{{"$and", bson.A{
+			policy.FilterAt("post."),
+			bson.D{{"post.status", bson.D{{"$nin", bson.A{model.DRAFT, model.SCHEDULED}}}}},
+		}}}}},
+		{{"$limit", page.Limit + 1}},
+	}
+
+	cursor, err := repo.getSavedPostsCollection().Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, "", err
+	}
+	var saves []struct {
+		ID      primitive.ObjectID `bson:"_id"`
+		SavedAt time.Time          `bson:"savedat"`
+		Post    model.Post         `bson:"post"`
+	}
+	if err = cursor.All(context.TODO(), &saves); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, "", err
+	}
+
+	posts := make([]model.Post, 0)
+	for i := 0; i < len(saves) && i < page.Limit; i++ {
+		posts = append(posts, saves[i].Post)
+	}
+	if len(saves) <= page.Limit {
+		return posts, "", nil
+	}
+	last := saves[page.Limit-1]
+	return posts, model.Cursor{PublishDate: last.SavedAt, ID: last.ID}.Encode(), nil
+}
+
+func (repo *PostRepository) CreateBookmarkCollection(ctx context.Context, collection *model.BookmarkCollection) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "CreateBookmarkCollection-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	_, err := repo.getBookmarkCollectionsCollection().InsertOne(context.TODO(), collection)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+	}
+	return err
+}
+
+func (repo *PostRepository) ReadBookmarkCollection(ctx context.Context, id primitive.ObjectID) (model.BookmarkCollection, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "ReadBookmarkCollection-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	var result model.BookmarkCollection
+	err := repo.getBookmarkCollectionsCollection().FindOne(context.TODO(), bson.D{{"_id", id}}).Decode(&result)
+	return result, err
+}
+
+func (repo *PostRepository) GetBookmarkCollections(ctx context.Context, profileID uint) ([]model.BookmarkCollection, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetBookmarkCollections-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	findOptions := options.Find().SetSort(bson.D{{"createdat", 1}})
+	cursor, err := repo.getBookmarkCollectionsCollection().Find(context.TODO(), bson.D{{"profileid", profileID}}, findOptions)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	collections := make([]model.BookmarkCollection, 0)
+	if err = cursor.All(context.TODO(), &collections); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	return collections, nil
+}
+
+// DeleteBookmarkCollection removes the collection together with the saves that were filed under it.
+func (repo *PostRepository) DeleteBookmarkCollection(ctx context.Context, id primitive.ObjectID) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "DeleteBookmarkCollection-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	if _, err := repo.getSavedPostsCollection().DeleteMany(context.TODO(), bson.D{{"collectionid", id}}); err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	_, err := repo.getBookmarkCollectionsCollection().DeleteOne(context.TODO(), bson.D{{"_id", id}})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+	}
+	return err
+}
+
+func (repo *PostRepository) getSavedPostsCollection() *mongo.Collection {
+	return repo.Client.Database(postDbName).Collection(savedPostsCollectionName)
+}
+
+func (repo *PostRepository) getBookmarkCollectionsCollection() *mongo.Collection {
+	return repo.Client.Database(postDbName).Collection(bookmarkCollectionsCollectionName)
+}
+
+// CountSavesByDay counts the profiles that saved the post in [from, to) by UTC day, each profile once a day however
+// many collections it saved the post into.
+func (repo *PostRepository) CountSavesByDay(ctx context.Context, postID primitive.ObjectID, from time.Time, to time.Time) (map[string]int, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "CountSavesByDay-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{"postid", postID}, {"savedat", bson.D{{"$gte", from}, {"$lt", to}}}}}},
+		{{"$group", bson.D{
+			{"_id", bson.D{{"$dateToString", bson.D{{"format", "%Y-%m-%d"}, {"date", "$savedat"}}}}},
+			{"profiles", bson.D{{"$addToSet", "$profileid"}}},
+		}}},
+	}
+	cursor, err := repo.getSavedPostsCollection().Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	var counts []struct {
+		Day      string `bson:"_id"`
+		Profiles []uint `bson:"profiles"`
+	}
+	if err = cursor.All(context.TODO(), &counts); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	ret := make(map[string]int)
+	for _, count := range counts {
+		ret[count.Day] = len(count.Profiles)
+	}
+	return ret, nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"nistagram/post/model"
+	"nistagram/post/visibility"
+	"nistagram/util"
+)
+
+// SearchPublic runs a text search over public posts; the query uses the Mongo text search syntax, so quoted phrases
+// must all be present and terms prefixed with a minus are excluded.
+func (repo *PostRepository) SearchPublic(ctx context.Context, query string, policy visibility.Policy, page model.SearchPage) ([]model.Post, string, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "SearchPublic-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{"$text", bson.D{{"$search", query}}}, {"$and", publicFilter(policy)}}}},
+		{{"$addFields", bson.D{{"score", bson.D{{"$meta", "textScore"}}}}}},
+	}
+	if page.Cursor != nil {
+		pipeline = append(pipeline, bson.D{{"$match", bson.D{{"$or", bson.A{
+			bson.D{{"score", bson.D{{"$lt", page.Cursor.Score}}}},
+			bson.D{{"score", page.Cursor.Score}, {"publishdate", bson.D{{"$lt", page.Cursor.PublishDate}}}},
+			bson.D{{"score", page.Cursor.Score}, {"publishdate", page.Cursor.PublishDate}, {"_id", bson.D{{"$lt", page.Cursor.ID}}}},
+		}}}}})
+	}
+	pipeline = append(pipeline,
+		bson.D{{"$sort", bson.D{{"score", -1}, {"publishdate", -1}, {"_id", -1}}}},
+		bson.D{{"$limit", page.Limit + 1}})
+
+	cursor, err := repo.getCollection().Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, "", err
+	}
+	var results []struct {
+		model.Post `bson:",inline"`
+		Score      float64 `bson:"score"`
+	}
+	if err = cursor.All(context.TODO(), &results); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, "", err
+	}
+
+	posts := make([]model.Post, 0)
+	for i := 0; i < len(results) && i < page.Limit; i++ {
+		posts = append(posts, results[i].Post)
+	}
+	if len(results) <= page.Limit {
+		return posts, "", nil
+	}
+	last := results[page.Limit-1]
+	return posts, model.SearchCursor{Score: last.Score, PublishDate: last.PublishDate, ID: last.ID}.Encode(), nil
+}
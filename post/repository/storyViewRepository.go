@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nistagram/post/model"
+	"nistagram/util"
+)
+
+const storyViewsCollectionName = "storyviews"
+
+// RecordStoryView is idempotent; watching a story again keeps the time of the first view.
+func (repo *PostRepository) RecordStoryView(ctx context.Context, view model.StoryView) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "RecordStoryView-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	filter := bson.D{{"storyid", view.StoryID}, {"viewerid", view.ViewerID}}
+	update := bson.D{{"$setOnInsert", bson.D{{"_id", view.ID}, {"viewedat", view.ViewedAt}, {"expiresat", view.ExpiresAt}}}}
+
+	_, err := repo.getStoryViewsCollection().UpdateOne(context.TODO(), filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		util.Tracer.LogError(span, err)
+	}
+	return err
+}
+
+func (repo *PostRepository) GetStoryViews(ctx context.Context, storyID primitive.ObjectID) ([]model.StoryView, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetStoryViews-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	findOptions := options.Find().SetSort(bson.D{{"viewedat", -1}})
+	cursor, err := repo.getStoryViewsCollection().Find(context.TODO(), bson.D{{"storyid", storyID}}, findOptions)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	views := make([]model.StoryView, 0)
+	if err = cursor.All(context.TODO(), &views); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	return views, nil
+}
+
+func (repo *PostRepository) getStoryViewsCollection() *mongo.Collection {
+	return repo.Client.Database(postDbName).Collection(storyViewsCollectionName)
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"nistagram/post/model"
+	"nistagram/post/visibility"
+	"nistagram/util"
+)
+
+// GetTaggedPosts returns the posts a profile is tagged in that the logged user may see; tags still waiting for
+// approval are only included when the tagged profile looks at its own tab.
+func (repo *PostRepository) GetTaggedPosts(ctx context.Context, policy visibility.Policy, taggedProfileID uint, includePending bool,
+	page model.Page) ([]model.Post, string, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetTaggedPosts-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	tagFilter := bson.D{{"usertags", bson.D{{"$elemMatch", bson.D{{"profileid", taggedProfileID}, {"approved", true}}}}}}
+	if includePending {
+		tagFilter = bson.D{{"usertags.profileid", taggedProfileID}}
+	}
+	conditions := bson.A{
+		policy.Filter(),
+		publishedFilter(),
+		tagFilter,
+	}
+
+	return repo.findPage(nextCtx, conditions, page)
+}
+
+func (repo *PostRepository) ApproveUserTag(ctx context.Context, postID primitive.ObjectID, profileID uint) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "ApproveUserTag-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	filter := bson.D{{"_id", postID}, {"isdeleted", false}, {"usertags.profileid", profileID}}
+	update := bson.D{{"$set", bson.D{{"usertags.$.approved", true}}}}
+
+	result, err := repo.getCollection().UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (repo *PostRepository) RemoveUserTag(ctx context.Context, postID primitive.ObjectID, profileID uint) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "RemoveUserTag-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	filter := bson.D{{"_id", postID}, {"isdeleted", false}, {"usertags.profileid", profileID}}
+	update := bson.D{{"$pull", bson.D{{"usertags", bson.D{{"profileid", profileID}}}}}}
+
+	result, err := repo.getCollection().UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// GetPostsWithTaggedUsernames returns the usernames of posts stored before tags were kept by profile ID, by post ID.
+func (repo *PostRepository) GetPostsWithTaggedUsernames(ctx context.Context) (map[primitive.ObjectID][]string, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetPostsWithTaggedUsernames-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	cursor, err := repo.getCollection().Find(context.TODO(), bson.D{{"taggedusers", bson.D{{"$exists", true}}}})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	var posts []struct {
+		ID          primitive.ObjectID `bson:"_id"`
+		TaggedUsers []string           `bson:"taggedusers"`
+	}
+	if err = cursor.All(context.TODO(), &posts); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	ret := make(map[primitive.ObjectID][]string)
+	for _, post := range posts {
+		ret[post.ID] = post.TaggedUsers
+	}
+	return ret, nil
+}
+
+func (repo *PostRepository) SetUserTags(ctx context.Context, postID primitive.ObjectID, userTags []model.UserTag) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "SetUserTags-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	update := bson.D{
+		{"$set", bson.D{{"usertags", userTags}}},
+		{"$unset", bson.D{{"taggedusers", ""}}},
+	}
+	_, err := repo.getCollection().UpdateOne(context.TODO(), bson.D{{"_id", postID}}, update)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+	}
+	return err
+}
@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"nistagram/post/model"
+	"nistagram/util"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timelines are sorted sets of posts scored by their publish date, kept for the profiles that opened their home feed
+// lately. The warm key marks a timeline that was built from the posts database; entries pushed to a timeline that
+// is not warm would be an incomplete feed, so they are left out until the timeline is built again.
+const (
+	MaxTimelineLength  = 800
+	timelineTTL        = 7 * 24 * time.Hour
+	timelineKeyPrefix  = "timeline:"
+	timelineWarmSuffix = ":warm"
+	celebritiesKey     = "timeline:celebrities"
+)
+
+type TimelineRepository struct {
+	Client *redis.Client
+}
+
+func (repo *TimelineRepository) IsWarm(ctx context.Context, profileID uint) (bool, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "IsWarm-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	count, err := repo.Client.Exists(context.TODO(), warmKey(profileID)).Result()
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Rebuild replaces the timeline of the profile with the given entries and marks it warm.
+func (repo *TimelineRepository) Rebuild(ctx context.Context, profileID uint, entries []model.TimelineEntry) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "Rebuild-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	key := timelineKey(profileID)
+	_, err := repo.Client.TxPipelined(context.TODO(), func(pipe redis.Pipeliner) error {
+		pipe.Del(context.TODO(), key)
+		addEntries(pipe, key, entries)
+		pipe.Set(context.TODO(), warmKey(profileID), 1, timelineTTL)
+		return nil
+	})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+	}
+	return err
+}
+
+// Push adds the entry to the warm timelines of the profiles.
+func (repo *TimelineRepository) Push(ctx context.Context, profileIDs []uint, entry model.TimelineEntry) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "Push-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	warm, err := repo.getWarm(context.TODO(), profileIDs)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if len(warm) == 0 {
+		return nil
+	}
+	_, err = repo.Client.Pipelined(context.TODO(), func(pipe redis.Pipeliner) error {
+		for _, profileID := range warm {
+			addEntries(pipe, timelineKey(profileID), []model.TimelineEntry{entry})
+		}
+		return nil
+	})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+	}
+	return err
+}
+
+// Add adds the entries to the timeline of the profile, if it is warm.
+func (repo *TimelineRepository) Add(ctx context.Context, profileID uint, entries []model.TimelineEntry) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "Add-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	warm, err := repo.IsWarm(nextCtx, profileID)
+	if err != nil || !warm || len(entries) == 0 {
+		return err
+	}
+	_, err = repo.Client.Pipelined(context.TODO(), func(pipe redis.Pipeliner) error {
+		addEntries(pipe, timelineKey(profileID), entries)
+		return nil
+	})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+	}
+	return err
+}
+
+// RemovePublisher takes all posts of the publisher out of the timeline of the profile.
+func (repo *TimelineRepository) RemovePublisher(ctx context.Context, profileID uint, publisherID uint) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "RemovePublisher-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	key := timelineKey(profileID)
+	members, err := repo.Client.ZRange(context.TODO(), key, 0, -1).Result()
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	prefix := model.TimelineMemberPrefix(publisherID)
+	removed := make([]interface{}, 0)
+	for _, member := range members {
+		if strings.HasPrefix(member, prefix) {
+			removed = append(removed, member)
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+	if err = repo.Client.ZRem(context.TODO(), key, removed...).Err(); err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	return nil
+}
+
+// Read returns at most count entries of the timeline published at or before the given time, newest first, and keeps
+// the timeline warm for another while.
+func (repo *TimelineRepository) Read(ctx context.Context, profileID uint, publishedBefore *time.Time, count int) ([]model.TimelineEntry, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "Read-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	key := timelineKey(profileID)
+	max := "+inf"
+	if publishedBefore != nil {
+		max = strconv.FormatInt(model.TimelineScore(*publishedBefore), 10)
+	}
+	var values *redis.ZSliceCmd
+	_, err := repo.Client.Pipelined(context.TODO(), func(pipe redis.Pipeliner) error {
+		values = pipe.ZRevRangeByScoreWithScores(context.TODO(), key, &redis.ZRangeBy{Min: "-inf", Max: max, Count: int64(count)})
+		pipe.Expire(context.TODO(), key, timelineTTL)
+		pipe.Expire(context.TODO(), warmKey(profileID), timelineTTL)
+		return nil
+	})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	entries := make([]model.TimelineEntry, 0)
+	for _, value := range values.Val() {
+		entry, err := model.ParseTimelineEntry(value.Member.(string), value.Score)
+		if err != nil {
+			util.Tracer.LogError(span, err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// SetCelebrity records whether the publisher has too many followers for its posts to be pushed to their timelines;
+// the feed reads the posts of celebrities when it is opened instead.
+func (repo *TimelineRepository) SetCelebrity(ctx context.Context, publisherID uint, celebrity bool) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "SetCelebrity-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	var err error
+	if celebrity {
+		err = repo.Client.SAdd(context.TODO(), celebritiesKey, publisherID).Err()
+	} else {
+		err = repo.Client.SRem(context.TODO(), celebritiesKey, publisherID).Err()
+	}
+	if err != nil {
+		util.Tracer.LogError(span, err)
+	}
+	return err
+}
+
+func (repo *TimelineRepository) GetCelebrities(ctx context.Context) ([]uint, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetCelebrities-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	members, err := repo.Client.SMembers(context.TODO(), celebritiesKey).Result()
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	ret := make([]uint, 0)
+	for _, member := range members {
+		id, err := strconv.ParseUint(member, 10, 64)
+		if err != nil {
+			continue
+		}
+		ret = append(ret, uint(id))
+	}
+	return ret, nil
+}
+
+func (repo *TimelineRepository) getWarm(ctx context.Context, profileIDs []uint) ([]uint, error) {
+	commands := make([]*redis.IntCmd, len(profileIDs))
+	_, err := repo.Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, profileID := range profileIDs {
+			commands[i] = pipe.Exists(ctx, warmKey(profileID))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	warm := make([]uint, 0)
+	for i, command := range commands {
+		if command.Val() > 0 {
+			warm = append(warm, profileIDs[i])
+		}
+	}
+	return warm, nil
+}
+
+// addEntries queues the entries and trims the timeline to its newest MaxTimelineLength entries.
+func addEntries(pipe redis.Pipeliner, key string, entries []model.TimelineEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	members := make([]*redis.Z, 0)
+	for _, entry := range entries {
+		members = append(members, &redis.Z{Score: entry.Score(), Member: entry.Member()})
+	}
+	pipe.ZAdd(context.TODO(), key, members...)
+	pipe.ZRemRangeByRank(context.TODO(), key, 0, -MaxTimelineLength-1)
+	pipe.Expire(context.TODO(), key, timelineTTL)
+}
+
+func timelineKey(profileID uint) string {
+	return timelineKeyPrefix + strconv.FormatUint(uint64(profileID), 10)
+}
+
+func warmKey(profileID uint) string {
+	return timelineKey(profileID) + timelineWarmSuffix
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nistagram/post/model"
+	"nistagram/util"
+	"time"
+)
+
+// GetTrash returns the posts the publisher deleted after the given time, the ones that can still be restored.
+func (repo *PostRepository) GetTrash(ctx context.Context, publisherId uint, deletedAfter time.Time, page model.Page) ([]model.Post, string, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetTrash-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	conditions := bson.A{
+		bson.D{{"isdeleted", true}},
+		bson.D{{"publisherid", publisherId}},
+		bson.D{{"deletedby", publisherId}},
+		bson.D{{"deletedat", bson.D{{"$gt", deletedAfter}}}},
+	}
+
+	return repo.findPage(nextCtx, conditions, page)
+}
+
+// Restore undeletes a post the publisher deleted after the given time and returns it as it was in the trash.
+func (repo *PostRepository) Restore(ctx context.Context, id primitive.ObjectID, publisherId uint, deletedAfter time.Time) (model.Post, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "Restore-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	filter := bson.D{{"_id", id}, {"isdeleted", true}, {"publisherid", publisherId}, {"deletedby", publisherId},
+		{"deletedat", bson.D{{"$gt", deletedAfter}}}}
+	update := bson.D{
+		{"$set", bson.D{
+			{"isdeleted", false},
+			{"deletedby", uint(0)},
+			{"deletedat", time.Time{}},
+		}},
+	}
+
+	var result model.Post
+	err := repo.getCollection().FindOneAndUpdate(context.TODO(), filter, update).Decode(&result)
+	if err != nil && err != mongo.ErrNoDocuments {
+		util.Tracer.LogError(span, err)
+	}
+	return result, err
+}
+
+// BackfillDeletedAt stamps posts deleted before the deletion time was stored, so the purge keeps them for a full retention period.
+func (repo *PostRepository) BackfillDeletedAt(ctx context.Context, now time.Time) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "BackfillDeletedAt-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	filter := bson.D{{"isdeleted", true}, {"deletedat", bson.D{{"$exists", false}}}}
+	update := bson.D{{"$set", bson.D{{"deletedat", now}}}}
+
+	_, err := repo.getCollection().UpdateMany(context.TODO(), filter, update)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+	}
+	return err
+}
+
+func (repo *PostRepository) GetPostsDeletedBefore(ctx context.Context, deletedBefore time.Time, limit int) ([]model.Post, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetPostsDeletedBefore-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	filter := bson.D{{"isdeleted", true}, {"deletedat", bson.D{{"$lt", deletedBefore}}}}
+	findOptions := options.Find().SetSort(bson.D{{"deletedat", 1}}).SetLimit(int64(limit))
+
+	cursor, err := repo.getCollection().Find(context.TODO(), filter, findOptions)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	posts := make([]model.Post, 0)
+	if err = cursor.All(context.TODO(), &posts); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	return posts, nil
+}
+
+// Purge hard-deletes a deleted post together with its saves, revisions and highlight entries.
+func (repo *PostRepository) Purge(ctx context.Context, id primitive.ObjectID) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "Purge-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	if _, err := repo.getSavedPostsCollection().DeleteMany(context.TODO(), bson.D{{"postid", id}}); err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if _, err := repo.getPostRevisionsCollection().DeleteMany(context.TODO(), bson.D{{"postid", id}}); err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	update := bson.D{{"$pull", bson.D{{"storyids", id}}}}
+	if _, err := repo.getHighlightsCollection().UpdateMany(context.TODO(), bson.D{{"storyids", id}}, update); err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if _, err := repo.getCollection().DeleteOne(context.TODO(), bson.D{{"_id", id}, {"isdeleted", true}}); err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"nistagram/post/dto"
+	"nistagram/post/model"
+	"nistagram/util"
+	"strings"
+)
+
+// ArrangeMedias reorders the slides of a post to the order of the given entries and updates their alt texts and
+// links; slides left out are removed, together with the tags placed on them. A post keeps at least one slide.
+func (service *PostService) ArrangeMedias(ctx context.Context, loggedUserID uint, id primitive.ObjectID, mediaDtos []dto.MediaDto) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "ArrangeMedias-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	post, err := service.PostRepository.Read(nextCtx, id)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if post.IsDeleted {
+		return mongo.ErrNoDocuments
+	}
+	if post.PublisherId != loggedUserID {
+		util.Tracer.LogError(span, fmt.Errorf("profile %d is not the publisher of post %s", loggedUserID, id.Hex()))
+		return ErrForbidden
+	}
+	if len(mediaDtos) == 0 {
+		util.Tracer.LogError(span, fmt.Errorf("post %s would have no media", id.Hex()))
+		return ErrInvalidInput
+	}
+
+	kept := make(map[primitive.ObjectID]bool)
+	medias := make([]model.Media, 0)
+	for _, mediaDto := range mediaDtos {
+		mediaID, err := primitive.ObjectIDFromHex(mediaDto.ID)
+		if err != nil || kept[mediaID] {
+			util.Tracer.LogError(span, fmt.Errorf("invalid or repeated media %s", mediaDto.ID))
+			return ErrInvalidInput
+		}
+		media, ok := findMedia(post.Medias, mediaID)
+		if !ok {
+			util.Tracer.LogError(span, fmt.Errorf("media %s is not on post %s", mediaDto.ID, id.Hex()))
+			return ErrInvalidInput
+		}
+		if err = applyMediaDto(&media, mediaDto); err != nil {
+			util.Tracer.LogError(span, err)
+			return err
+		}
+		kept[mediaID] = true
+		medias = append(medias, media)
+	}
+
+	removed := make([]model.Media, 0)
+	for _, media := range post.Medias {
+		if !kept[media.ID] {
+			removed = append(removed, media)
+		}
+	}
+	userTags := make([]model.UserTag, 0)
+	for _, userTag := range post.UserTags {
+		if userTag.MediaID == nil || kept[*userTag.MediaID] {
+			userTags = append(userTags, userTag)
+		}
+	}
+
+	post.SetMedias(medias)
+	if err = service.PostRepository.UpdateMedias(nextCtx, id, post.Medias, userTags); err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	service.DeleteMedia(nextCtx, removed)
+	return nil
+}
+
+func (service *PostService) BackfillMedias(ctx context.Context) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "BackfillMedias-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	return service.PostRepository.BackfillMedias(nextCtx)
+}
+
+// applyMediaDtos sets the alt texts and links sent with an upload on the media, in upload order.
+func applyMediaDtos(medias []model.Media, mediaDtos []dto.MediaDto) error {
+	if len(mediaDtos) > len(medias) {
+		return ErrInvalidInput
+	}
+	for i, mediaDto := range mediaDtos {
+		if err := applyMediaDto(&medias[i], mediaDto); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyMediaDto copies the alt text and the link of a slide; an empty link keeps the one the slide has.
+func applyMediaDto(media *model.Media, mediaDto dto.MediaDto) error {
+	altText := strings.TrimSpace(mediaDto.AltText)
+	if len(altText) > model.MaxAltTextLength {
+		return ErrInvalidInput
+	}
+	media.AltText = altText
+	if webSite := strings.TrimSpace(mediaDto.WebSite); webSite != "" {
+		media.WebSite = webSite
+	}
+	return nil
+}
+
+func findMedia(medias []model.Media, id primitive.ObjectID) (model.Media, bool) {
+	for _, media := range medias {
+		if media.ID == id {
+			return media, true
+		}
+	}
+	return model.Media{}, false
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"nistagram/post/dto"
+	"nistagram/post/model"
+	"nistagram/util"
+	"time"
+)
+
+func (service *PostService) GetDrafts(ctx context.Context, loggedUserID uint, page model.Page) (dto.PostPageDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetDrafts-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	drafts, nextCursor, err := service.PostRepository.GetDrafts(nextCtx, loggedUserID, page)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostPageDTO{}, err
+	}
+	ret := make([]dto.ResponsePostDTO, 0)
+	for _, draft := range drafts {
+		ret = append(ret, dto.ResponsePostDTO{Post: draft, Reaction: "none",
+			MediaURLs: service.getMediaURLs(nextCtx, draft.Medias)})
+	}
+	return dto.PostPageDTO{Posts: ret, NextCursor: nextCursor}, nil
+}
+
+// UpdateDraft edits a draft or scheduled post; its status decides whether it stays a draft, waits for its time or goes public now.
+func (service *PostService) UpdateDraft(ctx context.Context, loggedUserID uint, id primitive.ObjectID, postDto dto.PostDto) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "UpdateDraft-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	post, err := service.PostRepository.Read(nextCtx, id)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if post.IsPublished() {
+		util.Tracer.LogError(span, fmt.Errorf("post %s is already published", id.Hex()))
+		return mongo.ErrNoDocuments
+	}
+	if post.PublisherId != loggedUserID {
+		util.Tracer.LogError(span, fmt.Errorf("profile %d is not the publisher of post %s", loggedUserID, id.Hex()))
+		return ErrForbidden
+	}
+	if err = applyPostDto(nextCtx, &post, postDto); err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if err = applyPostStatus(&post, model.GetPostStatus(postDto.Status), postDto.ScheduledAt, time.Now()); err != nil {
+		util.Tracer.LogError(span, fmt.Errorf("invalid schedule"))
+		return err
+	}
+	if err = service.PostRepository.UpdateDraft(nextCtx, post); err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if !post.IsPublished() {
+		return nil
+	}
+	service.FanOutPost(nextCtx, post)
+	return service.PostRepository.UpdateHashTagCounts(nextCtx, post.Tags, 1, post.PublishDate)
+}
+
+func (service *PostService) PublishScheduledPosts(ctx context.Context) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "PublishScheduledPosts-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	for {
+		post, err := service.PostRepository.PublishDueScheduledPost(nextCtx, time.Now())
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		if err != nil {
+			util.Tracer.LogError(span, err)
+			return err
+		}
+		service.FanOutPost(nextCtx, post)
+		if err = service.PostRepository.UpdateHashTagCounts(nextCtx, post.Tags, 1, post.PublishDate); err != nil {
+			util.Tracer.LogError(span, err)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package service
+
+import "errors"
+
+var ErrForbidden = errors.New("FORBIDDEN")
+var ErrInvalidInput = errors.New("INVALID_INPUT")
+var ErrUnsupportedMedia = errors.New("UNSUPPORTED_MEDIA")
+var ErrMediaTooLarge = errors.New("MEDIA_TOO_LARGE")
+
+var errProfileNotFound = errors.New("PROFILE_NOT_FOUND")
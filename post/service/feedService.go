@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"io"
+	"math"
+	"net/http"
+	"nistagram/post/dto"
+	"nistagram/post/model"
+	"nistagram/util"
+	"sort"
+	"time"
+)
+
+// The ranked home feed scores the newest posts of the last few days; the score decays by half every recencyHalfLife
+// and grows with the likes and comments of the post, with close friends and with how often the viewer engaged with
+// the publisher before.
+const (
+	rankingWindow       = 72 * time.Hour
+	maxRankedCandidates = 300
+	recencyHalfLife     = 12 * time.Hour
+	likeWeight          = 1.0
+	commentWeight       = 2.0
+	closeFriendBoost    = 1.5
+	affinityWeight      = 0.5
+)
+
+type rankedPost struct {
+	post    model.Post
+	signals dto.RankingSignalsDTO
+}
+
+func (service *PostService) GetRankedPostsForHomePage(ctx context.Context, followingProfiles []util.FollowingProfileDTO, loggedUserID uint, page model.RankedPage) (dto.PostPageDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetRankedPostsForHomePage-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	rankedAt := time.Now()
+	if page.Cursor != nil {
+		rankedAt = page.Cursor.RankedAt
+	}
+	policy, err := service.GetVisibilityPolicy(nextCtx, followingProfiles, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostPageDTO{}, err
+	}
+	candidates, err := service.PostRepository.GetHomePageCandidates(nextCtx, policy, followedProfileIDs(followingProfiles),
+		rankedAt.Add(-rankingWindow), rankedAt, maxRankedCandidates)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostPageDTO{}, err
+	}
+	ranked, err := service.rankPosts(nextCtx, candidates, followingProfiles, loggedUserID, rankedAt)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostPageDTO{}, err
+	}
+
+	// Later pages continue after the score and ID of the last post returned instead of counting posts, so a post
+	// whose likes or comments changed in between moves within the feed without shifting the posts after it.
+	start := 0
+	if page.Cursor != nil {
+		for start < len(ranked) && !page.Cursor.IsAfter(ranked[start].signals.Score, ranked[start].post.ID) {
+			start++
+		}
+	}
+	ranked = ranked[start:]
+	nextCursor := ""
+	if len(ranked) > page.Limit {
+		ranked = ranked[:page.Limit]
+		last := ranked[len(ranked)-1]
+		nextCursor = model.RankedCursor{RankedAt: rankedAt, Score: last.signals.Score, ID: last.post.ID}.Encode()
+	}
+
+	posts := make([]model.Post, 0)
+	for _, value := range ranked {
+		posts = append(posts, value.post)
+	}
+	ret, err := service.getReactionsForPosts(nextCtx, posts, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostPageDTO{}, err
+	}
+	for i := range ret {
+		ret[i].Ranking = &ranked[i].signals
+	}
+	if !page.IsFirst() {
+		return dto.PostPageDTO{Posts: ret, NextCursor: nextCursor}, nil
+	}
+	ret, err = service.appendSponsoredPosts(nextCtx, ret, followingProfiles, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostPageDTO{}, err
+	}
+	return dto.PostPageDTO{Posts: ret, NextCursor: nextCursor}, nil
+}
+
+// rankPosts scores the posts and orders them by score, and posts with equal scores by ID, both descending.
+func (service *PostService) rankPosts(ctx context.Context, posts []model.Post, followingProfiles []util.FollowingProfileDTO, loggedUserID uint, rankedAt time.Time) ([]rankedPost, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "rankPosts-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	ranked := make([]rankedPost, 0)
+	if len(posts) == 0 {
+		return ranked, nil
+	}
+	postIDs := make([]string, 0)
+	for _, post := range posts {
+		postIDs = append(postIDs, post.ID.Hex())
+	}
+	engagement, err := getEngagement(nextCtx, postIDs)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	if len(engagement) != len(posts) {
+		util.Tracer.LogError(span, fmt.Errorf("bad lists"))
+		return nil, fmt.Errorf("BAD_LISTS")
+	}
+	affinity, err := service.getPublisherInteractions(nextCtx, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+
+	for i, post := range posts {
+		signals := rankingSignals(post, engagement[i], util.IsCloseFriend(followingProfiles, post.PublisherId),
+			affinity[post.PublisherId], rankedAt)
+		ranked = append(ranked, rankedPost{post: post, signals: signals})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].signals.Score != ranked[j].signals.Score {
+			return ranked[i].signals.Score > ranked[j].signals.Score
+		}
+		return ranked[i].post.ID.Hex() > ranked[j].post.ID.Hex()
+	})
+	return ranked, nil
+}
+
+func rankingSignals(post model.Post, engagement dto.EngagementDTO, closeFriend bool, interactions int, rankedAt time.Time) dto.RankingSignalsDTO {
+	age := rankedAt.Sub(post.PublishDate)
+	if age < 0 {
+		age = 0
+	}
+	signals := dto.RankingSignalsDTO{
+		AgeHours:          age.Hours(),
+		Recency:           math.Pow(0.5, age.Hours()/recencyHalfLife.Hours()),
+		Likes:             engagement.Likes,
+		Comments:          engagement.Comments,
+		Engagement:        1 + likeWeight*math.Log1p(float64(engagement.Likes)) + commentWeight*math.Log1p(float64(engagement.Comments)),
+		CloseFriend:       closeFriend,
+		CloseFriendFactor: 1,
+		Interactions:      interactions,
+		Affinity:          1 + affinityWeight*math.Log1p(float64(interactions)),
+	}
+	if closeFriend {
+		signals.CloseFriendFactor = closeFriendBoost
+	}
+	signals.Score = signals.Recency * signals.Engagement * signals.CloseFriendFactor * signals.Affinity
+	return signals
+}
+
+// getPublisherInteractions counts the likes and comments the profile left on the posts of each publisher.
+func (service *PostService) getPublisherInteractions(ctx context.Context, profileID uint) (map[uint]int, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "getPublisherInteractions-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	interactions, err := getInteractions(nextCtx, profileID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	counts := make(map[primitive.ObjectID]int)
+	ids := make([]primitive.ObjectID, 0)
+	for _, interaction := range interactions {
+		id, err := primitive.ObjectIDFromHex(interaction.PostID)
+		if err != nil {
+			continue
+		}
+		counts[id] += interaction.Count
+		ids = append(ids, id)
+	}
+	publishers, err := service.PostRepository.GetPublisherIDs(nextCtx, ids)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	ret := make(map[uint]int)
+	for id, publisherID := range publishers {
+		ret[publisherID] += counts[id]
+	}
+	return ret, nil
+}
+
+// appendSponsoredPosts adds the campaigns shown to the profile after the posts of the first page of its home feed.
+func (service *PostService) appendSponsoredPosts(ctx context.Context, posts []dto.ResponsePostDTO, followingProfiles []util.FollowingProfileDTO, loggedUserID uint) ([]dto.ResponsePostDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "appendSponsoredPosts-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	sponsoredPostsDTO, err := getCampaigns(nextCtx, loggedUserID, followingProfiles)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	sponsoredPosts, err := service.getSponsoredPosts(nextCtx, sponsoredPostsDTO, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	return append(posts, sponsoredPosts...), nil
+}
+
+func getEngagement(ctx context.Context, postIDs []string) ([]dto.EngagementDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "getEngagement-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	postReactionHost, postReactionPort := util.GetPostReactionHostAndPort()
+	postBody, _ := json.Marshal(map[string][]string{
+		"ids": postIDs,
+	})
+	resp, err := util.CrossServiceRequest(nextCtx, http.MethodPost,
+		util.GetCrossServiceProtocol()+"://"+postReactionHost+":"+postReactionPort+"/engagement",
+		postBody, map[string]string{"Content-Type": "application/json;"})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		util.Tracer.LogError(span, fmt.Errorf("engagement request failed with status %d", resp.StatusCode))
+		return nil, fmt.Errorf("ENGAGEMENT_UNAVAILABLE")
+	}
+
+	var engagement []dto.EngagementDTO
+	if err = json.NewDecoder(resp.Body).Decode(&engagement); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	return engagement, nil
+}
+
+func getInteractions(ctx context.Context, profileID uint) ([]dto.InteractionDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "getInteractions-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	postReactionHost, postReactionPort := util.GetPostReactionHostAndPort()
+	resp, err := util.CrossServiceRequest(nextCtx, http.MethodGet,
+		util.GetCrossServiceProtocol()+"://"+postReactionHost+":"+postReactionPort+"/interactions/"+util.Uint2String(profileID),
+		nil, map[string]string{})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		util.Tracer.LogError(span, fmt.Errorf("interactions request failed with status %d", resp.StatusCode))
+		return nil, fmt.Errorf("INTERACTIONS_UNAVAILABLE")
+	}
+
+	var interactions []dto.InteractionDTO
+	if err = json.NewDecoder(resp.Body).Decode(&interactions); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	return interactions, nil
+}
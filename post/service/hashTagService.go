@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"nistagram/post/dto"
+	"nistagram/post/model"
+	"nistagram/util"
+	"time"
+)
+
+const hashTagSuggestionsLimit = 10
+
+func (service *PostService) SuggestHashTags(ctx context.Context, query string) ([]model.HashTag, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "SuggestHashTags-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	prefix := model.NormalizeHashTag(query)
+	if prefix == "" {
+		return make([]model.HashTag, 0), nil
+	}
+	return service.PostRepository.SuggestHashTags(nextCtx, prefix, hashTagSuggestionsLimit)
+}
+
+func (service *PostService) GetHashTag(ctx context.Context, name string) (model.HashTag, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetHashTag-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	return service.PostRepository.GetHashTag(nextCtx, model.NormalizeHashTag(name))
+}
+
+func (service *PostService) GetTrendingHashTags(ctx context.Context, window time.Duration, limit int) ([]dto.HashTagTrendDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetTrendingHashTags-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	return service.PostRepository.GetTrendingHashTags(nextCtx, window, limit)
+}
+
+// BackfillHashTags parses the tags of posts created before tags were stored and counts them.
+func (service *PostService) BackfillHashTags(ctx context.Context) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "BackfillHashTags-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	posts, err := service.PostRepository.GetPostsWithoutTags(nextCtx)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	for _, post := range posts {
+		tags := model.ParseHashTags(post.Description, post.HashTags)
+		if err = service.PostRepository.SetPostTags(nextCtx, post.ID, tags); err != nil {
+			util.Tracer.LogError(span, err)
+			return err
+		}
+		if post.IsDeleted {
+			continue
+		}
+		if err = service.PostRepository.UpdateHashTagCounts(nextCtx, tags, 1, post.PublishDate); err != nil {
+			util.Tracer.LogError(span, err)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"nistagram/post/dto"
+	"nistagram/post/model"
+	"nistagram/util"
+	"time"
+)
+
+func (service *PostService) CreateHighlight(ctx context.Context, loggedUserID uint, highlightDto dto.HighlightDto) (model.Highlight, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "CreateHighlight-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	highlight := model.Highlight{ID: primitive.NewObjectID(), ProfileID: loggedUserID, CreatedAt: time.Now()}
+	if err := service.fillHighlight(nextCtx, &highlight, highlightDto); err != nil {
+		util.Tracer.LogError(span, err)
+		return model.Highlight{}, err
+	}
+	if err := service.PostRepository.CreateHighlight(nextCtx, &highlight); err != nil {
+		util.Tracer.LogError(span, err)
+		return model.Highlight{}, err
+	}
+	return highlight, nil
+}
+
+func (service *PostService) UpdateHighlight(ctx context.Context, loggedUserID uint, id primitive.ObjectID, highlightDto dto.HighlightDto) (model.Highlight, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "UpdateHighlight-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	highlight, err := service.PostRepository.ReadHighlight(nextCtx, id)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return model.Highlight{}, err
+	}
+	if highlight.ProfileID != loggedUserID {
+		util.Tracer.LogError(span, ErrForbidden)
+		return model.Highlight{}, ErrForbidden
+	}
+	if err = service.fillHighlight(nextCtx, &highlight, highlightDto); err != nil {
+		util.Tracer.LogError(span, err)
+		return model.Highlight{}, err
+	}
+	if err = service.PostRepository.UpdateHighlight(nextCtx, highlight); err != nil {
+		util.Tracer.LogError(span, err)
+		return model.Highlight{}, err
+	}
+	return highlight, nil
+}
+
+func (service *PostService) DeleteHighlight(ctx context.Context, loggedUserID uint, id primitive.ObjectID) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "DeleteHighlight-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	highlight, err := service.PostRepository.ReadHighlight(nextCtx, id)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if highlight.ProfileID != loggedUserID {
+		util.Tracer.LogError(span, ErrForbidden)
+		return ErrForbidden
+	}
+	return service.PostRepository.DeleteHighlight(nextCtx, id)
+}
+
+func (service *PostService) GetProfileHighlights(ctx context.Context, followingProfiles []util.FollowingProfileDTO, targetUsername string, loggedUserID uint) ([]dto.ResponseHighlightDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetProfileHighlights-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	ret := make([]dto.ResponseHighlightDTO, 0)
+	profileID, err := getProfileIDByUsername(nextCtx, targetUsername)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	policy, err := service.GetVisibilityPolicy(nextCtx, followingProfiles, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	if policy.IsBlocked(profileID) {
+		return ret, nil
+	}
+
+	highlights, err := service.PostRepository.GetProfileHighlights(nextCtx, profileID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	storyIDs := make([]primitive.ObjectID, 0)
+	for _, highlight := range highlights {
+		storyIDs = append(storyIDs, highlight.StoryIDs...)
+	}
+	stories, err := service.PostRepository.GetStoriesByIDs(nextCtx, storyIDs)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	visibleStories := make(map[primitive.ObjectID]model.Post)
+	for _, story := range stories {
+		if policy.CanSeeInHighlight(story) {
+			visibleStories[story.ID] = story
+		}
+	}
+
+	for _, highlight := range highlights {
+		highlightStories := make([]model.Post, 0)
+		for _, id := range highlight.StoryIDs {
+			if story, ok := visibleStories[id]; ok {
+				highlightStories = append(highlightStories, story)
+			}
+		}
+		if len(highlightStories) > 0 {
+			ret = append(ret, dto.ResponseHighlightDTO{Highlight: highlight, Stories: highlightStories})
+		}
+	}
+	return ret, nil
+}
+
+// fillHighlight copies the request into the highlight after checking that every story belongs to its owner.
+func (service *PostService) fillHighlight(ctx context.Context, highlight *model.Highlight, highlightDto dto.HighlightDto) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "fillHighlight-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	if highlightDto.Title == "" || len(highlightDto.StoryIDs) == 0 {
+		return ErrInvalidInput
+	}
+	storyIDs := make([]primitive.ObjectID, 0)
+	for _, value := range highlightDto.StoryIDs {
+		id, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			return ErrInvalidInput
+		}
+		storyIDs = append(storyIDs, id)
+	}
+	stories, err := service.PostRepository.GetStoriesByIDs(nextCtx, storyIDs)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	ownStories := make(map[primitive.ObjectID]model.Post)
+	for _, story := range stories {
+		if story.PublisherId == highlight.ProfileID && !story.IsDeleted {
+			ownStories[story.ID] = story
+		}
+	}
+	coverMedia := highlightDto.CoverMedia
+	coverFound := false
+	for _, id := range storyIDs {
+		story, ok := ownStories[id]
+		if !ok {
+			return ErrInvalidInput
+		}
+		for _, media := range story.Medias {
+			if coverMedia == "" {
+				coverMedia = media.FilePath
+			}
+			if media.FilePath == coverMedia {
+				coverFound = true
+			}
+		}
+	}
+	if !coverFound {
+		return ErrInvalidInput
+	}
+
+	highlight.Title = highlightDto.Title
+	highlight.CoverMedia = coverMedia
+	highlight.StoryIDs = storyIDs
+	return nil
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"io"
+	"net/http"
+	"net/url"
+	"nistagram/post/dto"
+	"nistagram/post/model"
+	"nistagram/util"
+	"time"
+)
+
+// Insights are bucketed by UTC day and cover at most MaxInsightsPeriod at once.
+const (
+	MaxInsightsPeriod = 90 * 24 * time.Hour
+	insightsDayLayout = "2006-01-02"
+	impressionEvent   = "IMPRESSION"
+	profileVisitEvent = "PROFILE_VISIT"
+)
+
+// GetPostInsights reports the impressions, reach, reactions, comments, saves and profile visits of a post by day.
+// Only the publisher of the post can read them.
+func (service *PostService) GetPostInsights(ctx context.Context, loggedUserID uint, id primitive.ObjectID, from time.Time, to time.Time) (dto.PostInsightsDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetPostInsights-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	if !from.Before(to) || to.Sub(from) > MaxInsightsPeriod {
+		util.Tracer.LogError(span, fmt.Errorf("invalid insights period %v - %v", from, to))
+		return dto.PostInsightsDTO{}, ErrInvalidInput
+	}
+	post, err := service.PostRepository.Read(nextCtx, id)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostInsightsDTO{}, err
+	}
+	if post.IsDeleted {
+		return dto.PostInsightsDTO{}, mongo.ErrNoDocuments
+	}
+	if post.PublisherId != loggedUserID {
+		util.Tracer.LogError(span, fmt.Errorf("profile %d is not the publisher of post %s", loggedUserID, id.Hex()))
+		return dto.PostInsightsDTO{}, ErrForbidden
+	}
+
+	monitored, err := getMonitoredInsights(nextCtx, id.Hex(), from, to)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostInsightsDTO{}, err
+	}
+	engagement, err := getDailyEngagement(nextCtx, id.Hex(), from, to)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostInsightsDTO{}, err
+	}
+	saves, err := service.PostRepository.CountSavesByDay(nextCtx, id, from, to)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostInsightsDTO{}, err
+	}
+
+	days := make(map[string]*dto.DailyInsightsDTO)
+	ret := dto.PostInsightsDTO{PostID: id.Hex(), From: from, To: to, Days: make([]dto.DailyInsightsDTO, 0)}
+	for day := from.UTC().Truncate(24 * time.Hour); day.Before(to); day = day.Add(24 * time.Hour) {
+		ret.Days = append(ret.Days, dto.DailyInsightsDTO{Day: day.Format(insightsDayLayout)})
+	}
+	for i := range ret.Days {
+		days[ret.Days[i].Day] = &ret.Days[i]
+	}
+	for _, value := range monitored.Days {
+		if day, ok := days[value.Day]; ok {
+			day.Impressions, day.Reach, day.ProfileVisits = value.Impressions, value.Reach, value.ProfileVisits
+		}
+	}
+	for _, value := range engagement {
+		if day, ok := days[value.Day]; ok {
+			day.Likes, day.Dislikes, day.Comments = value.Likes, value.Dislikes, value.Comments
+		}
+	}
+	for key, count := range saves {
+		if day, ok := days[key]; ok {
+			day.Saves = count
+		}
+	}
+
+	for _, day := range ret.Days {
+		ret.Totals.Impressions += day.Impressions
+		ret.Totals.Likes += day.Likes
+		ret.Totals.Dislikes += day.Dislikes
+		ret.Totals.Comments += day.Comments
+		ret.Totals.Saves += day.Saves
+		ret.Totals.ProfileVisits += day.ProfileVisits
+	}
+	ret.Totals.Reach = monitored.Reach
+	return ret, nil
+}
+
+// RecordProfileVisit counts a visit to the profile of the publisher that started from the post.
+func (service *PostService) RecordProfileVisit(ctx context.Context, followingProfiles []util.FollowingProfileDTO, loggedUserID uint, id primitive.ObjectID) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "RecordProfileVisit-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	policy, err := service.GetVisibilityPolicy(nextCtx, followingProfiles, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	post, err := service.ReadPost(nextCtx, policy, id)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if loggedUserID == 0 || post.PublisherId == loggedUserID {
+		return nil
+	}
+	return sendPostEvents(nextCtx, dto.PostEventsDTO{EventType: profileVisitEvent, ProfileID: loggedUserID,
+		PostIDs: []string{id.Hex()}})
+}
+
+// recordImpressions counts an impression for each of the posts shown to the viewer, leaving out the viewer's own
+// posts and campaigns, which are monitored on their own. Impressions are sent in the background and failures are
+// only logged.
+func recordImpressions(ctx context.Context, posts []model.Post, viewerID uint) {
+	postIDs := make([]string, 0)
+	for _, post := range posts {
+		if post.IsCampaign || (viewerID != 0 && post.PublisherId == viewerID) {
+			continue
+		}
+		postIDs = append(postIDs, post.ID.Hex())
+	}
+	if len(postIDs) == 0 {
+		return
+	}
+	go func() {
+		span := util.Tracer.StartSpanFromContext(ctx, "recordImpressions-service")
+		defer util.Tracer.FinishSpan(span)
+
+		nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+		err := sendPostEvents(nextCtx, dto.PostEventsDTO{EventType: impressionEvent, ProfileID: viewerID, PostIDs: postIDs})
+		if err != nil {
+			util.Tracer.LogError(span, err)
+		}
+	}()
+}
+
+func sendPostEvents(ctx context.Context, events dto.PostEventsDTO) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "sendPostEvents-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	monitoringHost, monitoringPort := util.GetMonitoringHostAndPort()
+	postBody, _ := json.Marshal(events)
+	resp, err := util.CrossServiceRequest(nextCtx, http.MethodPost,
+		util.GetCrossServiceProtocol()+"://"+monitoringHost+":"+monitoringPort+"/post-events",
+		postBody, map[string]string{"Content-Type": "application/json;"})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		util.Tracer.LogError(span, fmt.Errorf("post events request failed with status %d", resp.StatusCode))
+		return fmt.Errorf("POST_EVENTS_UNAVAILABLE")
+	}
+	return nil
+}
+
+func getMonitoredInsights(ctx context.Context, postID string, from time.Time, to time.Time) (dto.MonitoredInsightsDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "getMonitoredInsights-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	monitoringHost, monitoringPort := util.GetMonitoringHostAndPort()
+	resp, err := util.CrossServiceRequest(nextCtx, http.MethodGet,
+		util.GetCrossServiceProtocol()+"://"+monitoringHost+":"+monitoringPort+"/post-insights/"+postID+"?"+periodQuery(from, to),
+		nil, map[string]string{})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.MonitoredInsightsDTO{}, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		util.Tracer.LogError(span, fmt.Errorf("post insights request failed with status %d", resp.StatusCode))
+		return dto.MonitoredInsightsDTO{}, fmt.Errorf("INSIGHTS_UNAVAILABLE")
+	}
+
+	var insights dto.MonitoredInsightsDTO
+	if err = json.NewDecoder(resp.Body).Decode(&insights); err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.MonitoredInsightsDTO{}, err
+	}
+	return insights, nil
+}
+
+func getDailyEngagement(ctx context.Context, postID string, from time.Time, to time.Time) ([]dto.DailyEngagementDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "getDailyEngagement-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	postReactionHost, postReactionPort := util.GetPostReactionHostAndPort()
+	resp, err := util.CrossServiceRequest(nextCtx, http.MethodGet,
+		util.GetCrossServiceProtocol()+"://"+postReactionHost+":"+postReactionPort+"/engagement/"+postID+"/daily?"+periodQuery(from, to),
+		nil, map[string]string{})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		util.Tracer.LogError(span, fmt.Errorf("daily engagement request failed with status %d", resp.StatusCode))
+		return nil, fmt.Errorf("ENGAGEMENT_UNAVAILABLE")
+	}
+
+	var engagement []dto.DailyEngagementDTO
+	if err = json.NewDecoder(resp.Body).Decode(&engagement); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	return engagement, nil
+}
+
+func periodQuery(from time.Time, to time.Time) string {
+	return url.Values{"from": {from.Format(time.RFC3339)}, "to": {to.Format(time.RFC3339)}}.Encode()
+}
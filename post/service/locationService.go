@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"nistagram/post/dto"
+	"nistagram/post/model"
+	"nistagram/util"
+)
+
+const placesLimit = 20
+
+func (service *PostService) GetPublicNearby(ctx context.Context, point model.GeoPoint, radius float64, loggedUserID uint, page model.Page) (dto.PostPageDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetPublicNearby-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	policy, err := service.GetPublicPolicy(nextCtx, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostPageDTO{}, err
+	}
+	posts, nextCursor, err := service.PostRepository.GetPublicNearby(nextCtx, point, radius, policy, page)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostPageDTO{}, err
+	}
+	responseDTO, err := service.getReactionsForPosts(nextCtx, posts, loggedUserID)
+	return dto.PostPageDTO{Posts: responseDTO, NextCursor: nextCursor}, err
+}
+
+func (service *PostService) GetPublicPlaces(ctx context.Context, point model.GeoPoint, radius float64, loggedUserID uint) ([]dto.PlaceDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetPublicPlaces-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	policy, err := service.GetPublicPolicy(nextCtx, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	return service.PostRepository.GetPublicPlaces(nextCtx, point, radius, policy, placesLimit)
+}
+
+func (service *PostService) GetPublicByPlace(ctx context.Context, name string, loggedUserID uint, page model.Page) (dto.PostPageDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetPublicByPlace-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	policy, err := service.GetPublicPolicy(nextCtx, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostPageDTO{}, err
+	}
+	posts, nextCursor, err := service.PostRepository.GetPublicByPlace(nextCtx, name, policy, page)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostPageDTO{}, err
+	}
+	responseDTO, err := service.getReactionsForPosts(nextCtx, posts, loggedUserID)
+	return dto.PostPageDTO{Posts: responseDTO, NextCursor: nextCursor}, err
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"image"
+	"image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"nistagram/post/dto"
+	"nistagram/post/model"
+	"nistagram/util"
+	"nistagram/util/mediastore"
+	"time"
+)
+
+const MaxImageSize = 10 << 20
+const MaxVideoSize = 50 << 20
+const MaxImageDimension = 8192
+const MaxImagePixels = 40000000
+const mediaURLExpiry = 24 * time.Hour
+
+type thumbnailSize struct {
+	Name  string
+	Width int
+}
+
+var thumbnailSizes = []thumbnailSize{{"small", 150}, {"medium", 480}, {"large", 1080}}
+
+var mediaExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"video/mp4":  ".mp4",
+}
+
+// IngestMedia checks an upload by its content rather than its file name, strips image metadata,
+// stores the original together with its thumbnails and returns the media to attach to a post.
+// MP4 files are stored unchanged, since re-muxing video is out of reach here.
+func (service *PostService) IngestMedia(ctx context.Context, content io.Reader) (model.Media, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "IngestMedia-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	data, err := ioutil.ReadAll(io.LimitReader(content, MaxVideoSize+1))
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return model.Media{}, err
+	}
+	contentType := http.DetectContentType(data)
+	extension, ok := mediaExtensions[contentType]
+	if !ok {
+		util.Tracer.LogError(span, fmt.Errorf("unsupported content type %s", contentType))
+		return model.Media{}, ErrUnsupportedMedia
+	}
+	if len(data) > MaxVideoSize || (contentType != "video/mp4" && len(data) > MaxImageSize) {
+		util.Tracer.LogError(span, fmt.Errorf("%s upload of %d bytes is too large", contentType, len(data)))
+		return model.Media{}, ErrMediaTooLarge
+	}
+
+	name := uuid.NewString()
+	media := model.Media{ID: primitive.NewObjectID(), FilePath: name + extension, ContentType: contentType,
+		Kind: model.GetMediaKind(contentType)}
+	files := map[string][]byte{}
+	if contentType == "video/mp4" {
+		files[media.FilePath] = data
+	} else if err = processImage(&media, data, name, files); err != nil {
+		util.Tracer.LogError(span, err)
+		return model.Media{}, err
+	}
+	media.Size = int64(len(files[media.FilePath]))
+
+	for _, filePath := range media.FilePaths() {
+		file := files[filePath]
+		if err = service.MediaStore.Put(nextCtx, filePath, bytes.NewReader(file), int64(len(file)), http.DetectContentType(file)); err != nil {
+			util.Tracer.LogError(span, err)
+			service.DeleteMedia(nextCtx, []model.Media{media})
+			return model.Media{}, err
+		}
+	}
+	return media, nil
+}
+
+// DeleteMedia removes originals and thumbnails from the store; files that are already gone are skipped.
+func (service *PostService) DeleteMedia(ctx context.Context, medias []model.Media) {
+	span := util.Tracer.StartSpanFromContext(ctx, "DeleteMedia-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	for _, media := range medias {
+		for _, filePath := range media.FilePaths() {
+			if err := service.MediaStore.Delete(nextCtx, filePath); err != nil && err != mediastore.ErrNotFound {
+				util.Tracer.LogError(span, err)
+			}
+		}
+	}
+}
+
+func (service *PostService) getMediaURLs(ctx context.Context, medias []model.Media) []dto.MediaURLsDTO {
+	span := util.Tracer.StartSpanFromContext(ctx, "getMediaURLs-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	ret := make([]dto.MediaURLsDTO, 0)
+	for _, media := range medias {
+		urls := dto.MediaURLsDTO{MediaID: media.ID.Hex(), Thumbnails: map[string]string{}}
+		var err error
+		if urls.Original, err = service.MediaStore.SignedURL(nextCtx, media.FilePath, mediaURLExpiry); err != nil {
+			util.Tracer.LogError(span, err)
+		}
+		for _, thumbnail := range media.Thumbnails {
+			if url, err := service.MediaStore.SignedURL(nextCtx, thumbnail.FilePath, mediaURLExpiry); err != nil {
+				util.Tracer.LogError(span, err)
+			} else {
+				urls.Thumbnails[thumbnail.Name] = url
+			}
+		}
+		ret = append(ret, urls)
+	}
+	return ret
+}
+
+// processImage re-encodes the picture without its metadata and renders every thumbnail narrower than it.
+func processImage(media *model.Media, data []byte, name string, files map[string][]byte) error {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return ErrUnsupportedMedia
+	}
+	if config.Width > MaxImageDimension || config.Height > MaxImageDimension || config.Width*config.Height > MaxImagePixels {
+		return ErrMediaTooLarge
+	}
+
+	var pixels *image.NRGBA
+	if media.ContentType == "image/gif" {
+		animation, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return ErrUnsupportedMedia
+		}
+		var buffer bytes.Buffer
+		if err = gif.EncodeAll(&buffer, animation); err != nil {
+			return err
+		}
+		files[media.FilePath] = buffer.Bytes()
+		pixels = toNRGBA(animation.Image[0])
+	} else {
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return ErrUnsupportedMedia
+		}
+		pixels = toNRGBA(img)
+		if media.ContentType == "image/jpeg" {
+			pixels = applyOrientation(pixels, getJPEGOrientation(data))
+		}
+		if files[media.FilePath], err = encodeImage(pixels, media.ContentType); err != nil {
+			return err
+		}
+	}
+	media.Width, media.Height = pixels.Bounds().Dx(), pixels.Bounds().Dy()
+
+	thumbnailType, thumbnailExtension := "image/png", ".png"
+	if media.ContentType == "image/jpeg" {
+		thumbnailType, thumbnailExtension = "image/jpeg", ".jpg"
+	}
+	for _, size := range thumbnailSizes {
+		if size.Width >= media.Width {
+			continue
+		}
+		thumbnail := resize(pixels, size.Width)
+		filePath := name + "_" + size.Name + thumbnailExtension
+		if files[filePath], err = encodeImage(thumbnail, thumbnailType); err != nil {
+			return err
+		}
+		media.Thumbnails = append(media.Thumbnails, model.Thumbnail{Name: size.Name, Width: thumbnail.Bounds().Dx(),
+			Height: thumbnail.Bounds().Dy(), FilePath: filePath})
+	}
+	return nil
+}
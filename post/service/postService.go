@@ -3,325 +3,464 @@ package service
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"io"
 	"net/http"
 	"nistagram/post/dto"
 	"nistagram/post/model"
 	"nistagram/post/repository"
 	"nistagram/util"
+	"nistagram/util/mediastore"
 	"strings"
 	"time"
 )
 
 type PostService struct {
-	PostRepository *repository.PostRepository
+	PostRepository     *repository.PostRepository
+	MediaStore         mediastore.MediaStore
+	TrashRetention     time.Duration
+	Timeline           *repository.TimelineRepository
+	CelebrityThreshold int
 }
 
-func (service *PostService) GetPublic(ctx context.Context, loggedUserID uint) ([]dto.ResponsePostDTO, error) {
+func (service *PostService) GetPublic(ctx context.Context, loggedUserID uint, page model.Page) (dto.PostPageDTO, error) {
 	span := util.Tracer.StartSpanFromContext(ctx, "GetPublic-service")
 	defer util.Tracer.FinishSpan(span)
 
 	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
 
-	blockedRelationships, err := getProfilesBlockedRelationships(nextCtx, loggedUserID)
+	policy, err := service.GetPublicPolicy(nextCtx, loggedUserID)
 	if err != nil {
 		util.Tracer.LogError(span, err)
-		return nil, err
+		return dto.PostPageDTO{}, err
 	}
-	posts, err := service.PostRepository.GetPublic(nextCtx, blockedRelationships)
+	posts, nextCursor, err := service.PostRepository.GetPublic(nextCtx, policy, page)
 	if err != nil {
 		util.Tracer.LogError(span, err)
-		return nil, err
+		return dto.PostPageDTO{}, err
 	}
-	responseDTO, err := getReactionsForPosts(nextCtx, posts, loggedUserID)
-	return responseDTO, err
+	responseDTO, err := service.getReactionsForPosts(nextCtx, posts, loggedUserID)
+	return dto.PostPageDTO{Posts: responseDTO, NextCursor: nextCursor}, err
 }
 
-func (service *PostService) GetProfilesPosts(ctx context.Context, followingProfiles []util.FollowingProfileDTO, targetUsername string, loggedUserID uint) ([]dto.ResponsePostDTO, error) {
+func (service *PostService) GetProfilesPosts(ctx context.Context, followingProfiles []util.FollowingProfileDTO, targetUsername string, loggedUserID uint, page model.Page) (dto.PostPageDTO, error) {
 	span := util.Tracer.StartSpanFromContext(ctx, "GetProfilesPosts-service")
 	defer util.Tracer.FinishSpan(span)
 
 	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
 
-	profileID, err := getProfileIDByUsername(nextCtx, targetUsername)
-	if err != nil {
-		util.Tracer.LogError(span, err)
-		return nil, err
-	}
-	sponsoredPostsDTO, err := getCampaignsWhereUserIsInfluencer(nextCtx, profileID)
-	campaignPosts := make([]model.Post, 0)
-	influencerIDs := make([]uint, 0)
-	for _, sponsoredPostDTO := range sponsoredPostsDTO {
-		primitiveID, err := primitive.ObjectIDFromHex(sponsoredPostDTO.PostID)
+	ret := make([]dto.ResponsePostDTO, 0)
+	if page.IsFirst() {
+		profileID, err := getProfileIDByUsername(nextCtx, targetUsername)
 		if err != nil {
 			util.Tracer.LogError(span, err)
-			return nil, err
+			return dto.PostPageDTO{}, err
 		}
-		post, err := service.PostRepository.Read(nextCtx, primitiveID)
+		sponsoredPostsDTO, _ := getCampaignsWhereUserIsInfluencer(nextCtx, profileID)
+		ret, err = service.getSponsoredPosts(nextCtx, sponsoredPostsDTO, loggedUserID)
 		if err != nil {
 			util.Tracer.LogError(span, err)
-			return nil, err
+			return dto.PostPageDTO{}, err
 		}
-		influencerIDs = append(influencerIDs, sponsoredPostDTO.InfluencerID)
-		campaignPosts = append(campaignPosts, post)
 	}
-	initialSponsoredPosts, err := getReactionsForPosts(nextCtx, campaignPosts, loggedUserID)
+	policy, err := service.GetVisibilityPolicy(nextCtx, followingProfiles, loggedUserID)
 	if err != nil {
 		util.Tracer.LogError(span, err)
-		return nil, err
-	}
-	ret := make([]dto.ResponsePostDTO, 0)
-	influencerUsernames, err := getProfileUsernamesByIDs(nextCtx, influencerIDs)
-	for i, initial := range initialSponsoredPosts {
-		ret = append(ret, dto.ResponsePostDTO{
-			Post:               initial.Post,
-			Reaction:           initial.Reaction,
-			CampaignId:         sponsoredPostsDTO[i].CampaignID,
-			InfluencerId:       sponsoredPostsDTO[i].InfluencerID,
-			InfluencerUsername: influencerUsernames[i],
-		})
+		return dto.PostPageDTO{}, err
 	}
-	posts, err := service.PostRepository.GetProfilesPosts(nextCtx, followingProfiles, targetUsername)
+	posts, nextCursor, err := service.PostRepository.GetProfilesPosts(nextCtx, policy, targetUsername, page)
 	if err != nil {
 		util.Tracer.LogError(span, err)
-		return nil, err
+		return dto.PostPageDTO{}, err
 	}
-	postsDTO, err := getReactionsForPosts(nextCtx, posts, loggedUserID)
+	postsDTO, err := service.getReactionsForPosts(nextCtx, posts, loggedUserID)
 	if err != nil {
 		util.Tracer.LogError(span, err)
-		return nil, err
+		return dto.PostPageDTO{}, err
 	}
 	for _, post := range postsDTO {
 		ret = append(ret, post)
 	}
-	return ret, err
+	return dto.PostPageDTO{Posts: ret, NextCursor: nextCursor}, err
 }
 
-func (service *PostService) GetPublicPostByLocation(ctx context.Context, location string, loggedUserID uint) ([]dto.ResponsePostDTO, error) {
+func (service *PostService) GetPublicPostByLocation(ctx context.Context, location string, loggedUserID uint, page model.Page) (dto.PostPageDTO, error) {
 	span := util.Tracer.StartSpanFromContext(ctx, "GetPublicPostByLocation-service")
 	defer util.Tracer.FinishSpan(span)
 
 	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
 
-	blockedRelationships, err := getProfilesBlockedRelationships(nextCtx, loggedUserID)
+	policy, err := service.GetPublicPolicy(nextCtx, loggedUserID)
 	if err != nil {
 		util.Tracer.LogError(span, err)
-		return nil, err
+		return dto.PostPageDTO{}, err
 	}
-	posts, err := service.PostRepository.GetPublicPostByLocation(nextCtx, location, blockedRelationships)
+	posts, nextCursor, err := service.PostRepository.GetPublicPostByLocation(nextCtx, location, policy, page)
 	if err != nil {
 		util.Tracer.LogError(span, err)
-		return nil, err
+		return dto.PostPageDTO{}, err
 	}
-	responseDTO, err := getReactionsForPosts(nextCtx, posts, loggedUserID)
-	return responseDTO, err
+	responseDTO, err := service.getReactionsForPosts(nextCtx, posts, loggedUserID)
+	return dto.PostPageDTO{Posts: responseDTO, NextCursor: nextCursor}, err
 }
 
-func (service *PostService) GetPublicPostByHashTag(ctx context.Context, hashTag string, loggedUserID uint) ([]dto.ResponsePostDTO, error) {
+func (service *PostService) GetPublicPostByHashTag(ctx context.Context, hashTag string, loggedUserID uint, page model.Page) (dto.PostPageDTO, error) {
 	span := util.Tracer.StartSpanFromContext(ctx, "GetPublicPostByHashTag-service")
 	defer util.Tracer.FinishSpan(span)
 
 	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
 
-	blockedRelationships, err := getProfilesBlockedRelationships(nextCtx, loggedUserID)
+	policy, err := service.GetPublicPolicy(nextCtx, loggedUserID)
 	if err != nil {
 		util.Tracer.LogError(span, err)
-		return nil, err
+		return dto.PostPageDTO{}, err
 	}
-	posts, err := service.PostRepository.GetPublicPostByHashTag(nextCtx, hashTag, blockedRelationships)
+	posts, nextCursor, err := service.PostRepository.GetPublicPostByHashTag(nextCtx, hashTag, policy, page)
 	if err != nil {
 		util.Tracer.LogError(span, err)
-		return nil, err
+		return dto.PostPageDTO{}, err
 	}
-	responseDTO, err := getReactionsForPosts(nextCtx, posts, loggedUserID)
-	return responseDTO, err
+	responseDTO, err := service.getReactionsForPosts(nextCtx, posts, loggedUserID)
+	return dto.PostPageDTO{Posts: responseDTO, NextCursor: nextCursor}, err
 }
 
-func (service *PostService) GetMyPosts(ctx context.Context, loggedUserID uint) ([]dto.ResponsePostDTO, error) {
-	span := util.Tracer.StartSpanFromContext(ctx, "GetMyPosts-service")
+func (service *PostService) SearchPublic(ctx context.Context, query string, loggedUserID uint, page model.SearchPage) (dto.PostPageDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "SearchPublic-service")
 	defer util.Tracer.FinishSpan(span)
 
 	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
 
-	sponsoredPostsDTO, err := getCampaignsWhereUserIsInfluencer(nextCtx, loggedUserID)
+	query = strings.TrimSpace(query)
+	if query == "" || len(query) > model.MaxSearchQueryLength {
+		util.Tracer.LogError(span, fmt.Errorf("invalid search query"))
+		return dto.PostPageDTO{}, ErrInvalidInput
+	}
+	policy, err := service.GetPublicPolicy(nextCtx, loggedUserID)
 	if err != nil {
 		util.Tracer.LogError(span, err)
+		return dto.PostPageDTO{}, err
 	}
-	campaignPosts := make([]model.Post, 0)
-	influencerIDs := make([]uint, 0)
-	for _, sponsoredPostDTO := range sponsoredPostsDTO {
-		primitiveID, err := primitive.ObjectIDFromHex(sponsoredPostDTO.PostID)
+	posts, nextCursor, err := service.PostRepository.SearchPublic(nextCtx, query, policy, page)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostPageDTO{}, err
+	}
+	responseDTO, err := service.getReactionsForPosts(nextCtx, posts, loggedUserID)
+	return dto.PostPageDTO{Posts: responseDTO, NextCursor: nextCursor}, err
+}
+
+func (service *PostService) GetMyPosts(ctx context.Context, loggedUserID uint, page model.Page) (dto.PostPageDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetMyPosts-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	ret := make([]dto.ResponsePostDTO, 0)
+	if page.IsFirst() {
+		sponsoredPostsDTO, err := getCampaignsWhereUserIsInfluencer(nextCtx, loggedUserID)
 		if err != nil {
 			util.Tracer.LogError(span, err)
-			return nil, err
 		}
-		post, err := service.PostRepository.Read(nextCtx, primitiveID)
+		ret, err = service.getSponsoredPosts(nextCtx, sponsoredPostsDTO, loggedUserID)
 		if err != nil {
 			util.Tracer.LogError(span, err)
-			return nil, err
+			return dto.PostPageDTO{}, err
 		}
-		influencerIDs = append(influencerIDs, sponsoredPostDTO.InfluencerID)
-		campaignPosts = append(campaignPosts, post)
 	}
-	initialSponsoredPosts, err := getReactionsForPosts(nextCtx, campaignPosts, loggedUserID)
+	posts, nextCursor, err := service.PostRepository.GetMyPosts(nextCtx, loggedUserID, page)
 	if err != nil {
 		util.Tracer.LogError(span, err)
-		return nil, err
+		return dto.PostPageDTO{}, err
 	}
-	ret := make([]dto.ResponsePostDTO, 0)
-	influencerUsernames, err := getProfileUsernamesByIDs(nextCtx, influencerIDs)
+	postsDTO, err := service.getReactionsForPosts(nextCtx, posts, loggedUserID)
 	if err != nil {
 		util.Tracer.LogError(span, err)
+		return dto.PostPageDTO{}, err
 	}
-	for i, initial := range initialSponsoredPosts {
-		ret = append(ret, dto.ResponsePostDTO{
-			Post:               initial.Post,
-			Reaction:           initial.Reaction,
-			CampaignId:         sponsoredPostsDTO[i].CampaignID,
-			InfluencerId:       sponsoredPostsDTO[i].InfluencerID,
-			InfluencerUsername: influencerUsernames[i],
-		})
+	for _, post := range postsDTO {
+		ret = append(ret, post)
 	}
-	posts, err := service.PostRepository.GetMyPosts(nextCtx, loggedUserID)
+	return dto.PostPageDTO{Posts: ret, NextCursor: nextCursor}, err
+}
+
+func (service *PostService) GetStoryArchive(ctx context.Context, loggedUserID uint, page model.Page) (dto.PostPageDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetStoryArchive-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	stories, nextCursor, err := service.PostRepository.GetStoryArchive(nextCtx, loggedUserID, page)
 	if err != nil {
 		util.Tracer.LogError(span, err)
-		return nil, err
+		return dto.PostPageDTO{}, err
 	}
-	postsDTO, err := getReactionsForPosts(nextCtx, posts, loggedUserID)
+	ret := make([]dto.ResponsePostDTO, 0)
+	for _, story := range stories {
+		ret = append(ret, dto.ResponsePostDTO{Post: story, Reaction: "none", IsEdited: story.IsEdited(),
+			MediaURLs: service.getMediaURLs(nextCtx, story.Medias)})
+	}
+	return dto.PostPageDTO{Posts: ret, NextCursor: nextCursor}, nil
+}
+
+func (service *PostService) BackfillStoryExpiry(ctx context.Context) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "BackfillStoryExpiry-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	return service.PostRepository.BackfillStoryExpiry(nextCtx)
+}
+
+func (service *PostService) ArchiveExpiredStories(ctx context.Context) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "ArchiveExpiredStories-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	count, err := service.PostRepository.ArchiveExpiredStories(nextCtx, time.Now())
 	if err != nil {
 		util.Tracer.LogError(span, err)
-		return nil, err
+		return err
 	}
-	for _, post := range postsDTO {
-		ret = append(ret, post)
+	if count > 0 {
+		fmt.Println("Archived", count, "expired stories")
 	}
-	return ret, err
+	return nil
 }
 
-func (service *PostService) GetPostsForHomePage(ctx context.Context, followingProfiles []util.FollowingProfileDTO, loggedUserID uint) ([]dto.ResponsePostDTO, error) {
-	span := util.Tracer.StartSpanFromContext(ctx, "GetPostsForHomePage-service")
+func (service *PostService) PurgeArchivedStories(ctx context.Context, retention time.Duration) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "PurgeArchivedStories-service")
 	defer util.Tracer.FinishSpan(span)
 
 	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
 
-	posts, err := service.PostRepository.GetPostsForHomePage(nextCtx, followingProfiles)
+	stories, err := service.PostRepository.PurgeArchivedStories(nextCtx, time.Now().Add(-retention))
 	if err != nil {
 		util.Tracer.LogError(span, err)
-		return nil, err
+		return err
 	}
-	ret := make([]dto.ResponsePostDTO, 0)
-	ret, err = getReactionsForPosts(nextCtx, posts, loggedUserID)
-	if err != nil {
+	for _, story := range stories {
+		service.DeleteMedia(nextCtx, story.Medias)
+	}
+	return nil
+}
+
+func (service *PostService) CreatePost(ctx context.Context, postType model.PostType, post dto.PostDto, medias []model.Media, profile dto.ProfileDto) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "CreatePost-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	for i := 0; i < len(medias) && i < len(post.Links); i++ {
+		medias[i].WebSite = post.Links[i]
+	}
+	if err := applyMediaDtos(medias, post.Medias); err != nil {
 		util.Tracer.LogError(span, err)
-		return nil, err
+		return err
 	}
-	sponsoredPostsDTO, err := getCampaigns(nextCtx, loggedUserID, followingProfiles)
+
+	userTags, err := resolveUserTags(nextCtx, profile.ProfileId, post.Description, post.UserTags, medias, nil)
 	if err != nil {
 		util.Tracer.LogError(span, err)
-		return nil, err
+		return err
 	}
-	campaignPosts := make([]model.Post, 0)
-	influencerIDs := make([]uint, 0)
-	for _, sponsoredPostDTO := range sponsoredPostsDTO {
-		primitiveID, err := primitive.ObjectIDFromHex(sponsoredPostDTO.PostID)
-		if err != nil {
-			util.Tracer.LogError(span, err)
-			return nil, err
-		}
-		post, err := service.PostRepository.Read(nextCtx, primitiveID)
-		if err != nil {
-			util.Tracer.LogError(span, err)
-			return nil, err
+
+	newPost := model.Post{ID: primitive.NewObjectID(), PublisherId: profile.ProfileId, PublisherUsername: profile.Username,
+		PostType: postType, PublishDate: time.Now(),
+		Description: post.Description, IsHighlighted: post.IsHighlighted, IsCampaign: false,
+		IsCloseFriendsOnly: post.IsCloseFriendsOnly, Location: post.Location,
+		HashTags: post.HashTags, Tags: model.ParseHashTags(post.Description, post.HashTags),
+		UserTags: userTags,
+		IsPrivate: profile.ProfileSettings.IsPrivate, IsDeleted: false}
+	newPost.SetMedias(medias)
+	if err := applySensitiveCategory(&newPost, post.SensitiveCategory); err != nil {
+		util.Tracer.LogError(span, fmt.Errorf("invalid sensitive category"))
+		return err
+	}
+	if err := applyPostStatus(&newPost, model.GetPostStatus(post.Status), post.ScheduledAt, newPost.PublishDate); err != nil {
+		util.Tracer.LogError(span, fmt.Errorf("invalid schedule"))
+		return err
+	}
+	if post.Latitude != nil && post.Longitude != nil {
+		if !model.IsValidCoordinate(*post.Latitude, *post.Longitude) {
+			util.Tracer.LogError(span, fmt.Errorf("invalid coordinates"))
+			return ErrInvalidInput
 		}
-		influencerIDs = append(influencerIDs, sponsoredPostDTO.InfluencerID)
-		campaignPosts = append(campaignPosts, post)
+		newPost.GeoLocation = &model.Location{Name: post.Location, Point: model.NewGeoPoint(*post.Latitude, *post.Longitude)}
 	}
-	initialSponsoredPosts, err := getReactionsForPosts(nextCtx, campaignPosts, loggedUserID)
-	if err != nil {
+
+	if err := service.PostRepository.Create(nextCtx, &newPost); err != nil {
 		util.Tracer.LogError(span, err)
-		return nil, err
+		return err
 	}
-	influencerUsernames, err := getProfileUsernamesByIDs(nextCtx, influencerIDs)
-	for i, initial := range initialSponsoredPosts {
-		ret = append(ret, dto.ResponsePostDTO{
-			Post:               initial.Post,
-			Reaction:           initial.Reaction,
-			CampaignId:         sponsoredPostsDTO[i].CampaignID,
-			InfluencerId:       sponsoredPostsDTO[i].InfluencerID,
-			InfluencerUsername: influencerUsernames[i],
-		})
+	if !newPost.IsPublished() {
+		return nil
 	}
-	return ret, err
+	service.FanOutPost(nextCtx, newPost)
+	return service.PostRepository.UpdateHashTagCounts(nextCtx, newPost.Tags, 1, newPost.PublishDate)
 }
 
-func (service *PostService) CreatePost(ctx context.Context, postType model.PostType, post dto.PostDto, mediaNames []string, profile dto.ProfileDto) error {
-	span := util.Tracer.StartSpanFromContext(ctx, "CreatePost-service")
+// DeletePost moves the post to the trash; deletedBy tells whether its publisher can still restore it. The open
+// reports of the post are closed as actioned by its removal.
+func (service *PostService) DeletePost(ctx context.Context, id primitive.ObjectID, deletedBy uint) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "DeletePost-service")
 	defer util.Tracer.FinishSpan(span)
 
 	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
 
-	var medias []model.Media
-	for i := 0; i < len(mediaNames); i++ {
-		m := model.Media{
-			ID:       primitive.NewObjectID(),
-			FilePath: mediaNames[i],
-			WebSite:  post.Links[i],
-		}
-		medias = append(medias, m)
+	if err := service.trashPost(nextCtx, id, deletedBy); err != nil {
+		return err
 	}
+	return deletePostsReports(nextCtx, id)
+}
 
-	if strings.Contains(post.Description, "@") {
-		err := canUsersBeTagged(nextCtx, post.Description, profile.ProfileId)
-		if err != nil {
+func (service *PostService) trashPost(ctx context.Context, id primitive.ObjectID, deletedBy uint) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "trashPost-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	post, err := service.PostRepository.Read(nextCtx, id)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	err = service.PostRepository.Delete(nextCtx, id, deletedBy, time.Now())
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if post.IsPublished() {
+		if err = service.PostRepository.UpdateHashTagCounts(nextCtx, post.Tags, -1, time.Now()); err != nil {
 			util.Tracer.LogError(span, err)
-			return err
 		}
 	}
-
-	newPost := model.Post{ID: primitive.NewObjectID(), PublisherId: profile.ProfileId, PublisherUsername: profile.Username,
-		PostType: postType, Medias: medias, PublishDate: time.Now(),
-		Description: post.Description, IsHighlighted: post.IsHighlighted, IsCampaign: false,
-		IsCloseFriendsOnly: post.IsCloseFriendsOnly, Location: post.Location,
-		HashTags: post.HashTags, IsPrivate: profile.ProfileSettings.IsPrivate, IsDeleted: false}
-
-	return service.PostRepository.Create(nextCtx, &newPost)
+	return nil
 }
 
-func (service *PostService) ReadPost(ctx context.Context, id primitive.ObjectID) (model.Post, error) {
-	span := util.Tracer.StartSpanFromContext(ctx, "ReadPost-service")
+func (service *PostService) UpdatePost(ctx context.Context, loggedUserID uint, id primitive.ObjectID, postDto dto.PostDto) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "UpdatePost-service")
 	defer util.Tracer.FinishSpan(span)
 
 	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
 
-	return service.PostRepository.Read(nextCtx, id)
+	post, err := service.PostRepository.Read(nextCtx, id)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if post.PublisherId != loggedUserID {
+		util.Tracer.LogError(span, fmt.Errorf("profile %d is not the publisher of post %s", loggedUserID, id.Hex()))
+		return ErrForbidden
+	}
+	if !post.IsPublished() {
+		util.Tracer.LogError(span, fmt.Errorf("post %s is not published, drafts are edited as drafts", id.Hex()))
+		return ErrInvalidInput
+	}
+
+	now := time.Now()
+	revisions := make([]model.PostRevision, 0)
+	if !post.IsEdited() {
+		revisions = append(revisions, model.NewPostRevision(post, post.PublisherId, post.PublishDate))
+	}
+
+	oldTags := post.Tags
+	if err = applyPostDto(nextCtx, &post, postDto); err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	post.EditedAt = now
+	revisions = append(revisions, model.NewPostRevision(post, loggedUserID, now))
+
+	if err = service.PostRepository.Update(nextCtx, post); err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if err = service.PostRepository.CreateRevisions(nextCtx, revisions); err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	removedTags, addedTags := diffTags(oldTags, post.Tags)
+	if err = service.PostRepository.UpdateHashTagCounts(nextCtx, removedTags, -1, now); err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	return service.PostRepository.UpdateHashTagCounts(nextCtx, addedTags, 1, now)
 }
 
-func (service *PostService) DeletePost(ctx context.Context, id primitive.ObjectID) error {
-	span := util.Tracer.StartSpanFromContext(ctx, "DeletePost-service")
+// applyPostDto copies the editable fields of a post, checking tagged users and coordinates on the way.
+func applyPostDto(ctx context.Context, post *model.Post, postDto dto.PostDto) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "applyPostDto-service")
 	defer util.Tracer.FinishSpan(span)
 
 	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
 
-	err := service.PostRepository.Delete(nextCtx, id)
+	userTags, err := resolveUserTags(nextCtx, post.PublisherId, postDto.Description, postDto.UserTags, post.Medias, post.UserTags)
 	if err != nil {
 		util.Tracer.LogError(span, err)
 		return err
 	}
+	if postDto.Latitude != nil && postDto.Longitude != nil {
+		if !model.IsValidCoordinate(*postDto.Latitude, *postDto.Longitude) {
+			util.Tracer.LogError(span, fmt.Errorf("invalid coordinates"))
+			return ErrInvalidInput
+		}
+		post.GeoLocation = &model.Location{Name: postDto.Location, Point: model.NewGeoPoint(*postDto.Latitude, *postDto.Longitude)}
+	} else if postDto.Location != post.Location {
+		post.GeoLocation = nil
+	}
+	post.Description = postDto.Description
+	post.HashTags = postDto.HashTags
+	post.Tags = model.ParseHashTags(postDto.Description, postDto.HashTags)
+	post.Location = postDto.Location
+	post.UserTags = userTags
+	post.IsHighlighted = postDto.IsHighlighted
+	post.IsCloseFriendsOnly = postDto.IsCloseFriendsOnly
+	if err = applySensitiveCategory(post, postDto.SensitiveCategory); err != nil {
+		util.Tracer.LogError(span, fmt.Errorf("invalid sensitive category"))
+		return err
+	}
+	return nil
+}
 
-	err = deletePostsReports(nextCtx, id)
-	return err
+// applyPostStatus decides when a post goes public: now, at its scheduled time, or not until it is edited again.
+func applyPostStatus(post *model.Post, status model.PostStatus, scheduledAt *time.Time, now time.Time) error {
+	post.Status = status
+	post.ScheduledAt = time.Time{}
+	post.PublishDate = now
+	post.ExpiresAt = time.Time{}
+	switch status {
+	case model.SCHEDULED:
+		if scheduledAt == nil || !scheduledAt.After(now) {
+			return ErrInvalidInput
+		}
+		post.ScheduledAt = *scheduledAt
+	case model.PUBLISHED:
+		if post.PostType == model.STORY {
+			post.ExpiresAt = now.Add(model.StoryDuration)
+		}
+	}
+	return nil
 }
 
-func (service *PostService) UpdatePost(ctx context.Context, id primitive.ObjectID, post dto.PostDto) error {
-	span := util.Tracer.StartSpanFromContext(ctx, "UpdatePost-service")
+func (service *PostService) GetPostRevisions(ctx context.Context, followingProfiles []util.FollowingProfileDTO, loggedUserID uint, id primitive.ObjectID) ([]model.PostRevision, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetPostRevisions-service")
 	defer util.Tracer.FinishSpan(span)
 
 	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
 
-	return service.PostRepository.Update(nextCtx, id, post)
+	policy, err := service.GetVisibilityPolicy(nextCtx, followingProfiles, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	if _, err = service.ReadPost(nextCtx, policy, id); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	return service.PostRepository.GetRevisions(nextCtx, id)
 }
 
 func (service *PostService) DeleteUserPosts(ctx context.Context, profileId uint) error {
@@ -330,13 +469,13 @@ func (service *PostService) DeleteUserPosts(ctx context.Context, profileId uint)
 
 	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
 
-	posts, err := service.PostRepository.GetMyPosts(nextCtx, profileId)
+	posts, err := service.PostRepository.GetAllPublisherPosts(nextCtx, profileId)
 	if err != nil {
 		util.Tracer.LogError(span, err)
 		return err
 	}
 	for i := 0; i < len(posts); i++ {
-		err = service.DeletePost(nextCtx, posts[i].ID)
+		err = service.DeletePost(nextCtx, posts[i].ID, 0)
 		if err != nil {
 			util.Tracer.LogError(span, err)
 			return err
@@ -392,6 +531,64 @@ func (service *PostService) GetMediaById(ctx context.Context, mediaId string) (m
 	return service.PostRepository.GetMediaById(nextCtx, mediaId)
 }
 
+func (service *PostService) getSponsoredPosts(ctx context.Context, sponsoredPostsDTO []dto.SponsoredPostsDTO, loggedUserID uint) ([]dto.ResponsePostDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "getSponsoredPosts-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	// Campaigns whose post was deleted or archived are left out instead of failing the feed they are added to.
+	campaignPosts := make([]model.Post, 0)
+	influencerIDs := make([]uint, 0)
+	foundPostsDTO := make([]dto.SponsoredPostsDTO, 0)
+	for _, sponsoredPostDTO := range sponsoredPostsDTO {
+		primitiveID, err := primitive.ObjectIDFromHex(sponsoredPostDTO.PostID)
+		if err != nil {
+			util.Tracer.LogError(span, err)
+			return nil, err
+		}
+		post, err := service.PostRepository.Read(nextCtx, primitiveID)
+		if err == mongo.ErrNoDocuments {
+			util.Tracer.LogError(span, fmt.Errorf("campaign post %s not found", sponsoredPostDTO.PostID))
+			continue
+		}
+		if err != nil {
+			util.Tracer.LogError(span, err)
+			return nil, err
+		}
+		influencerIDs = append(influencerIDs, sponsoredPostDTO.InfluencerID)
+		campaignPosts = append(campaignPosts, post)
+		foundPostsDTO = append(foundPostsDTO, sponsoredPostDTO)
+	}
+	sponsoredPostsDTO = foundPostsDTO
+	initialSponsoredPosts, err := service.getReactionsForPosts(nextCtx, campaignPosts, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	ret := make([]dto.ResponsePostDTO, 0)
+	if len(initialSponsoredPosts) == 0 {
+		return ret, nil
+	}
+	influencerUsernames, err := getProfileUsernamesByIDs(nextCtx, influencerIDs)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	for i, initial := range initialSponsoredPosts {
+		ret = append(ret, dto.ResponsePostDTO{
+			Post:               initial.Post,
+			Reaction:           initial.Reaction,
+			IsEdited:           initial.IsEdited,
+			MediaURLs:          initial.MediaURLs,
+			CampaignId:         sponsoredPostsDTO[i].CampaignID,
+			InfluencerId:       sponsoredPostsDTO[i].InfluencerID,
+			InfluencerUsername: influencerUsernames[i],
+		})
+	}
+	return ret, nil
+}
+
 func deletePostsReports(ctx context.Context, postId primitive.ObjectID) error {
 	span := util.Tracer.StartSpanFromContext(ctx, "deletePostsReports-service")
 	defer util.Tracer.FinishSpan(span)
@@ -405,65 +602,29 @@ func deletePostsReports(ctx context.Context, postId primitive.ObjectID) error {
 	return err
 }
 
-func canUsersBeTagged(ctx context.Context, description string, publisherId uint) error {
-	span := util.Tracer.StartSpanFromContext(ctx, "canUsersBeTagged-service")
-	defer util.Tracer.FinishSpan(span)
-
-	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
-
-	var followingProfiles []uint
-
-	resp, err := getUserFollowers(nextCtx, publisherId)
-	if err != nil {
-		util.Tracer.LogError(span, err)
-		return err
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		util.Tracer.LogError(span, err)
-		return err
+// diffTags returns the tags that were dropped from and added to a post by an edit.
+func diffTags(oldTags []string, newTags []string) ([]string, []string) {
+	removed, added := make([]string, 0), make([]string, 0)
+	for _, tag := range oldTags {
+		if !containsString(newTags, tag) {
+			removed = append(removed, tag)
+		}
 	}
-	defer func(Body io.ReadCloser) {
-		_ = Body.Close()
-	}(resp.Body)
-
-	if err = json.Unmarshal(body, &followingProfiles); err != nil {
-		util.Tracer.LogError(span, err)
-		return err
+	for _, tag := range newTags {
+		if !containsString(oldTags, tag) {
+			added = append(added, tag)
+		}
 	}
+	return removed, added
+}
 
-	descriptionParts := strings.Split(description, " ")
-	for i := 0; i < len(descriptionParts); i++ {
-		if strings.Contains(descriptionParts[i], "@") {
-			taggedUsername := descriptionParts[i][1:len(descriptionParts[i])]
-			var taggedProfile dto.ProfileDto
-			if resp, err := getProfileByUsername(nextCtx, taggedUsername); err != nil {
-				util.Tracer.LogError(span, err)
-				return err
-			} else {
-				body, _ := io.ReadAll(resp.Body)
-				defer func(Body io.ReadCloser) {
-					_ = Body.Close()
-				}(resp.Body)
-				if err := json.Unmarshal(body, &taggedProfile); err != nil {
-					util.Tracer.LogError(span, err)
-					return err
-				}
-			}
-
-			if !taggedProfile.ProfileSettings.CanBeTagged {
-				util.Tracer.LogError(span, fmt.Errorf("%s can't be tagged",taggedProfile.Username))
-				return errors.New(taggedProfile.Username + " can't be tagged!")
-			}
-
-			if !util.Contains(followingProfiles, taggedProfile.ProfileId) {
-				util.Tracer.LogError(span, fmt.Errorf("%s is not followed by this profile",taggedProfile.Username))
-				return errors.New(taggedProfile.Username + " is not followed by this profile!")
-			}
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
 		}
 	}
-	return nil
+	return false
 }
 
 func getUserFollowers(ctx context.Context, loggedUserId uint) (*http.Response, error) {
@@ -492,7 +653,7 @@ func getProfileByUsername(ctx context.Context, username string) (*http.Response,
 	return resp, err
 }
 
-func getReactionsForPosts(ctx context.Context, posts []model.Post, profileID uint) ([]dto.ResponsePostDTO, error) {
+func (service *PostService) getReactionsForPosts(ctx context.Context, posts []model.Post, profileID uint) ([]dto.ResponsePostDTO, error) {
 	span := util.Tracer.StartSpanFromContext(ctx, "getReactionsForPosts-service")
 	defer util.Tracer.FinishSpan(span)
 
@@ -501,37 +662,50 @@ func getReactionsForPosts(ctx context.Context, posts []model.Post, profileID uin
 	if len(posts) == 0 {
 		return make([]dto.ResponsePostDTO, 0), nil
 	}
-	if profileID == 0 {
-		ret := make([]dto.ResponsePostDTO, len(posts))
-		for i, value := range posts {
-			ret[i].Reaction = "none"
-			ret[i].Post = value
-		}
-		return ret, nil
-	}
+	// Every list of posts served to a viewer comes through here, so this is where their impressions are counted.
+	recordImpressions(nextCtx, posts, profileID)
 	postIDs := make([]string, 0)
 	for _, value := range posts {
 		postIDs = append(postIDs, util.GetStringIDFromMongoID(value.ID))
 	}
-	postReactionHost, postReactionPort := util.GetPostReactionHostAndPort()
-	postBody, _ := json.Marshal(map[string][]string{
-		"ids": postIDs,
-	})
-	resp, err := util.CrossServiceRequest(nextCtx, http.MethodPost,
-		util.GetCrossServiceProtocol()+"://"+postReactionHost+":"+postReactionPort+"/get-reaction-types/"+util.Uint2String(profileID),
-		postBody, map[string]string{"Content-Type": "application/json;"})
-
+	counts, err := getPostCounts(nextCtx, postIDs, profileID)
 	if err != nil {
 		util.Tracer.LogError(span, err)
 		return nil, err
 	}
+	if len(posts) != len(counts) {
+		util.Tracer.LogError(span, fmt.Errorf("bad lists"))
+		return nil, fmt.Errorf("BAD_LISTS")
+	}
+	ret := make([]dto.ResponsePostDTO, 0)
 
-	if resp.StatusCode != 200 {
-		util.Tracer.LogError(span, fmt.Errorf("bad post id"))
-		return nil, fmt.Errorf("BAD_POST_ID")
+	for i, value := range posts {
+		ret = append(ret, dto.ResponsePostDTO{
+			Post:      value,
+			Reaction:  counts[i].Reaction,
+			IsEdited:  value.IsEdited(),
+			MediaURLs: service.getMediaURLs(nextCtx, value.Medias),
+			Counts:    &counts[i].CountsDTO,
+		})
 	}
+	setContentWarnings(nextCtx, ret, profileID)
+	return ret, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
+// getPostCounts reads the reaction and comment counters of the posts, with the reactions the viewer left on them.
+func getPostCounts(ctx context.Context, postIDs []string, viewerID uint) ([]dto.PostCountsDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "getPostCounts-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	postReactionHost, postReactionPort := util.GetPostReactionHostAndPort()
+	postBody, _ := json.Marshal(map[string][]string{
+		"ids": postIDs,
+	})
+	resp, err := util.CrossServiceRequest(nextCtx, http.MethodPost,
+		util.GetCrossServiceProtocol()+"://"+postReactionHost+":"+postReactionPort+"/counts?viewer="+util.Uint2String(viewerID),
+		postBody, map[string]string{"Content-Type": "application/json;"})
 	if err != nil {
 		util.Tracer.LogError(span, err)
 		return nil, err
@@ -539,25 +713,17 @@ func getReactionsForPosts(ctx context.Context, posts []model.Post, profileID uin
 	defer func(Body io.ReadCloser) {
 		_ = Body.Close()
 	}(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		util.Tracer.LogError(span, fmt.Errorf("counts request failed with status %d", resp.StatusCode))
+		return nil, fmt.Errorf("COUNTS_UNAVAILABLE")
+	}
 
-	var reactions []string
-	if err = json.Unmarshal(body, &reactions); err != nil {
+	var counts []dto.PostCountsDTO
+	if err = json.NewDecoder(resp.Body).Decode(&counts); err != nil {
 		util.Tracer.LogError(span, err)
 		return nil, err
 	}
-	if len(posts) != len(reactions) {
-		util.Tracer.LogError(span, fmt.Errorf("bad lists"))
-		return nil, fmt.Errorf("BAD_LISTS")
-	}
-	ret := make([]dto.ResponsePostDTO, 0)
-
-	for i, value := range posts {
-		ret = append(ret, dto.ResponsePostDTO{
-			Post:     value,
-			Reaction: reactions[i],
-		})
-	}
-	return ret, nil
+	return counts, nil
 }
 
 func getProfilesBlockedRelationships(ctx context.Context, loggedProfileId uint) ([]uint, error) {
@@ -718,6 +884,7 @@ func getProfileUsernamesByIDs(ctx context.Context, profileIDs []uint) ([]string,
 	return ret, nil
 }
 
+// getProfileIDByUsername returns errProfileNotFound only when the profile service confirms there is no such profile.
 func getProfileIDByUsername(ctx context.Context, username string) (uint, error) {
 	span := util.Tracer.StartSpanFromContext(ctx, "getProfileIDByUsername-service")
 	defer util.Tracer.FinishSpan(span)
@@ -731,6 +898,14 @@ func getProfileIDByUsername(ctx context.Context, username string) (uint, error)
 		util.Tracer.LogError(span, err)
 		return 0, err
 	}
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, errProfileNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("reading profile %s failed with status %d", username, resp.StatusCode)
+		util.Tracer.LogError(span, err)
+		return 0, err
+	}
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		util.Tracer.LogError(span, err)
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"nistagram/post/dto"
+	"nistagram/post/model"
+	"nistagram/util"
+	"strings"
+	"time"
+)
+
+const maxBookmarkCollectionNameLength = 50
+
+func (service *PostService) SavePost(ctx context.Context, followingProfiles []util.FollowingProfileDTO, loggedUserID uint, savePostDto dto.SavePostDto) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "SavePost-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	postID, err := primitive.ObjectIDFromHex(savePostDto.PostID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return ErrInvalidInput
+	}
+	collectionID, err := service.getBookmarkCollectionID(nextCtx, loggedUserID, savePostDto.CollectionID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	policy, err := service.GetVisibilityPolicy(nextCtx, followingProfiles, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	post, err := service.ReadPost(nextCtx, policy, postID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if !post.IsPublished() {
+		return mongo.ErrNoDocuments
+	}
+
+	return service.PostRepository.SavePost(nextCtx, model.SavedPost{ID: primitive.NewObjectID(), ProfileID: loggedUserID,
+		PostID: postID, CollectionID: collectionID, SavedAt: time.Now()})
+}
+
+func (service *PostService) UnsavePost(ctx context.Context, loggedUserID uint, postID primitive.ObjectID, collection string) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "UnsavePost-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	collectionID, err := service.getBookmarkCollectionID(nextCtx, loggedUserID, collection)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	return service.PostRepository.UnsavePost(nextCtx, loggedUserID, postID, collectionID)
+}
+
+func (service *PostService) GetSavedPosts(ctx context.Context, followingProfiles []util.FollowingProfileDTO, loggedUserID uint, collection string, page model.Page) (dto.PostPageDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetSavedPosts-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	collectionID, err := service.getBookmarkCollectionID(nextCtx, loggedUserID, collection)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostPageDTO{}, err
+	}
+	policy, err := service.GetVisibilityPolicy(nextCtx, followingProfiles, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostPageDTO{}, err
+	}
+	posts, nextCursor, err := service.PostRepository.GetSavedPosts(nextCtx, loggedUserID, collectionID, policy, page)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostPageDTO{}, err
+	}
+	responseDTO, err := service.getReactionsForPosts(nextCtx, posts, loggedUserID)
+	return dto.PostPageDTO{Posts: responseDTO, NextCursor: nextCursor}, err
+}
+
+func (service *PostService) CreateBookmarkCollection(ctx context.Context, loggedUserID uint, collectionDto dto.BookmarkCollectionDto) (model.BookmarkCollection, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "CreateBookmarkCollection-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	name := strings.TrimSpace(collectionDto.Name)
+	if name == "" || len(name) > maxBookmarkCollectionNameLength {
+		util.Tracer.LogError(span, fmt.Errorf("invalid collection name"))
+		return model.BookmarkCollection{}, ErrInvalidInput
+	}
+	collection := model.BookmarkCollection{ID: primitive.NewObjectID(), ProfileID: loggedUserID, Name: name, CreatedAt: time.Now()}
+	if err := service.PostRepository.CreateBookmarkCollection(nextCtx, &collection); err != nil {
+		util.Tracer.LogError(span, err)
+		return model.BookmarkCollection{}, err
+	}
+	return collection, nil
+}
+
+func (service *PostService) GetBookmarkCollections(ctx context.Context, loggedUserID uint) ([]model.BookmarkCollection, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetBookmarkCollections-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	return service.PostRepository.GetBookmarkCollections(nextCtx, loggedUserID)
+}
+
+func (service *PostService) DeleteBookmarkCollection(ctx context.Context, loggedUserID uint, id primitive.ObjectID) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "DeleteBookmarkCollection-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	if _, err := service.getBookmarkCollectionID(nextCtx, loggedUserID, id.Hex()); err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	return service.PostRepository.DeleteBookmarkCollection(nextCtx, id)
+}
+
+// getBookmarkCollectionID resolves a collection owned by the logged user; an empty value means the default collection.
+func (service *PostService) getBookmarkCollectionID(ctx context.Context, loggedUserID uint, collection string) (primitive.ObjectID, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "getBookmarkCollectionID-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	if collection == "" {
+		return primitive.NilObjectID, nil
+	}
+	id, err := primitive.ObjectIDFromHex(collection)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return primitive.NilObjectID, ErrInvalidInput
+	}
+	bookmarkCollection, err := service.PostRepository.ReadBookmarkCollection(nextCtx, id)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return primitive.NilObjectID, err
+	}
+	if bookmarkCollection.ProfileID != loggedUserID {
+		return primitive.NilObjectID, ErrForbidden
+	}
+	return id, nil
+}
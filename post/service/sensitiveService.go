@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"nistagram/post/model"
+	"nistagram/util"
+)
+
+// MarkSensitive lets a moderator flag a post as sensitive, or clear the flag; the publisher can not change the
+// category a moderator chose afterwards.
+func (service *PostService) MarkSensitive(ctx context.Context, moderatorID uint, id primitive.ObjectID, category string) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "MarkSensitive-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	sensitiveCategory, ok := model.GetSensitiveCategory(category)
+	if !ok {
+		util.Tracer.LogError(span, fmt.Errorf("invalid sensitive category %s", category))
+		return ErrInvalidInput
+	}
+	markedBy := moderatorID
+	if sensitiveCategory == model.NOT_SENSITIVE {
+		markedBy = 0
+	}
+	return service.PostRepository.UpdateSensitiveCategory(nextCtx, id, sensitiveCategory, markedBy)
+}
+
+// applySensitiveCategory sets the category the publisher chose, unless a moderator already flagged the post.
+func applySensitiveCategory(post *model.Post, category string) error {
+	sensitiveCategory, ok := model.GetSensitiveCategory(category)
+	if !ok {
+		return ErrInvalidInput
+	}
+	if post.IsMarkedSensitiveByModerator() {
+		return nil
+	}
+	post.SensitiveCategory = sensitiveCategory
+	post.SensitiveMarkedBy = 0
+	if post.IsSensitive() {
+		post.SensitiveMarkedBy = post.PublisherId
+	}
+	return nil
+}
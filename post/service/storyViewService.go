@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"nistagram/post/dto"
+	"nistagram/post/model"
+	"nistagram/util"
+	"time"
+)
+
+func (service *PostService) ViewStory(ctx context.Context, followingProfiles []util.FollowingProfileDTO, loggedUserID uint, id primitive.ObjectID) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "ViewStory-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	now := time.Now()
+	story, err := service.readLiveStory(nextCtx, id, now)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if story.PublisherId == loggedUserID {
+		return nil
+	}
+	policy, err := service.GetVisibilityPolicy(nextCtx, followingProfiles, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if !policy.CanSee(story) {
+		util.Tracer.LogError(span, fmt.Errorf("profile %d cannot see story %s", loggedUserID, id.Hex()))
+		return mongo.ErrNoDocuments
+	}
+
+	view := model.StoryView{ID: primitive.NewObjectID(), StoryID: story.ID, ViewerID: loggedUserID,
+		ViewedAt: now, ExpiresAt: story.ExpiresAt}
+	return service.PostRepository.RecordStoryView(nextCtx, view)
+}
+
+// GetStoryViewers lists who watched a live story, leaving out profiles the publisher is in a block relationship with.
+func (service *PostService) GetStoryViewers(ctx context.Context, loggedUserID uint, id primitive.ObjectID) (dto.StoryViewersDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetStoryViewers-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	story, err := service.readLiveStory(nextCtx, id, time.Now())
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.StoryViewersDTO{}, err
+	}
+	if story.PublisherId != loggedUserID {
+		util.Tracer.LogError(span, fmt.Errorf("profile %d is not the publisher of story %s", loggedUserID, id.Hex()))
+		return dto.StoryViewersDTO{}, ErrForbidden
+	}
+	views, err := service.PostRepository.GetStoryViews(nextCtx, id)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.StoryViewersDTO{}, err
+	}
+	blockedRelationships, err := getProfilesBlockedRelationships(nextCtx, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.StoryViewersDTO{}, err
+	}
+
+	viewers := make([]dto.StoryViewerDTO, 0)
+	viewerIDs := make([]uint, 0)
+	for _, view := range views {
+		if util.Contains(blockedRelationships, view.ViewerID) {
+			continue
+		}
+		viewers = append(viewers, dto.StoryViewerDTO{ProfileID: view.ViewerID, ViewedAt: view.ViewedAt})
+		viewerIDs = append(viewerIDs, view.ViewerID)
+	}
+	if len(viewerIDs) > 0 {
+		usernames, err := getProfileUsernamesByIDs(nextCtx, viewerIDs)
+		if err != nil {
+			util.Tracer.LogError(span, err)
+			return dto.StoryViewersDTO{}, err
+		}
+		for i := 0; i < len(viewers) && i < len(usernames); i++ {
+			viewers[i].Username = usernames[i]
+		}
+	}
+	return dto.StoryViewersDTO{Viewers: viewers, Count: len(viewers)}, nil
+}
+
+func (service *PostService) readLiveStory(ctx context.Context, id primitive.ObjectID, now time.Time) (model.Post, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "readLiveStory-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	story, err := service.PostRepository.Read(nextCtx, id)
+	if err != nil {
+		return story, err
+	}
+	if story.PostType != model.STORY || !story.IsPublished() || !story.ExpiresAt.After(now) {
+		return story, mongo.ErrNoDocuments
+	}
+	return story, nil
+}
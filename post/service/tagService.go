@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"io"
+	"net/http"
+	"nistagram/post/dto"
+	"nistagram/post/model"
+	"nistagram/util"
+	"strings"
+)
+
+func (service *PostService) GetTaggedPosts(ctx context.Context, followingProfiles []util.FollowingProfileDTO, targetUsername string, loggedUserID uint, page model.Page) (dto.PostPageDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetTaggedPosts-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	target, err := getProfileByUsernameDto(nextCtx, targetUsername)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostPageDTO{}, err
+	}
+	policy, err := service.GetVisibilityPolicy(nextCtx, followingProfiles, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostPageDTO{}, err
+	}
+	if policy.IsBlocked(target.ProfileId) || target.ProfileSettings.IsPrivate && !policy.Follows(target.ProfileId) {
+		return dto.PostPageDTO{Posts: make([]dto.ResponsePostDTO, 0)}, nil
+	}
+
+	ownProfile := target.ProfileId == loggedUserID
+	posts, nextCursor, err := service.PostRepository.GetTaggedPosts(nextCtx, policy, target.ProfileId, ownProfile, page)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostPageDTO{}, err
+	}
+	responseDTO, err := service.getReactionsForPosts(nextCtx, posts, loggedUserID)
+	return dto.PostPageDTO{Posts: responseDTO, NextCursor: nextCursor}, err
+}
+
+func (service *PostService) ApproveUserTag(ctx context.Context, loggedUserID uint, postID primitive.ObjectID) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "ApproveUserTag-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	return service.PostRepository.ApproveUserTag(nextCtx, postID, loggedUserID)
+}
+
+func (service *PostService) RemoveUserTag(ctx context.Context, loggedUserID uint, postID primitive.ObjectID) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "RemoveUserTag-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	return service.PostRepository.RemoveUserTag(nextCtx, postID, loggedUserID)
+}
+
+// BackfillUserTags converts the usernames posts were tagged with before tags were stored by profile ID. Those tags
+// were never subject to approval, so they are kept as approved. Only usernames the profile service confirms missing
+// are dropped; when a lookup fails the run stops and leaves the remaining usernames for the next run.
+func (service *PostService) BackfillUserTags(ctx context.Context) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "BackfillUserTags-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	posts, err := service.PostRepository.GetPostsWithTaggedUsernames(nextCtx)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	profileIDs := make(map[string]uint)
+	for postID, usernames := range posts {
+		userTags := make([]model.UserTag, 0)
+		for _, username := range usernames {
+			profileID, ok := profileIDs[username]
+			if !ok {
+				profileID, err = getProfileIDByUsername(nextCtx, username)
+				if err != nil && err != errProfileNotFound {
+					util.Tracer.LogError(span, err)
+					return err
+				}
+				profileIDs[username] = profileID
+			}
+			if profileID != 0 && !containsUserTag(userTags, profileID) {
+				userTags = append(userTags, model.UserTag{ProfileID: profileID, Approved: true})
+			}
+		}
+		if err = service.PostRepository.SetUserTags(nextCtx, postID, userTags); err != nil {
+			util.Tracer.LogError(span, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveUserTags turns the mentions in a description and the tags placed on media into tags by profile ID. Every
+// tagged profile must allow tagging and be followed by the publisher. Tags that were already on the post keep their
+// approval, new ones wait for the tagged profile to approve them.
+func resolveUserTags(ctx context.Context, publisherId uint, description string, tagDtos []dto.UserTagDto, medias []model.Media, current []model.UserTag) ([]model.UserTag, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "resolveUserTags-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	userTags := make([]model.UserTag, 0)
+	mentions := getMentionedUsernames(description)
+	if len(mentions) == 0 && len(tagDtos) == 0 {
+		return userTags, nil
+	}
+
+	var followingProfiles []uint
+	resp, err := getUserFollowers(nextCtx, publisherId)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	if err = json.Unmarshal(body, &followingProfiles); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+
+	for _, tagDto := range tagDtos {
+		userTag := model.UserTag{ProfileID: tagDto.ProfileID}
+		if tagDto.MediaIndex != nil {
+			if *tagDto.MediaIndex < 0 || *tagDto.MediaIndex >= len(medias) || !model.IsValidTagPosition(tagDto.X, tagDto.Y) {
+				util.Tracer.LogError(span, fmt.Errorf("invalid tag position"))
+				return nil, ErrInvalidInput
+			}
+			mediaID := medias[*tagDto.MediaIndex].ID
+			userTag.MediaID, userTag.X, userTag.Y = &mediaID, tagDto.X, tagDto.Y
+		}
+		if containsUserTag(userTags, userTag.ProfileID) {
+			continue
+		}
+		taggedProfile, err := getProfileByID(nextCtx, tagDto.ProfileID)
+		if err != nil {
+			util.Tracer.LogError(span, err)
+			return nil, err
+		}
+		if err = canBeTagged(taggedProfile, followingProfiles); err != nil {
+			util.Tracer.LogError(span, err)
+			return nil, err
+		}
+		userTags = append(userTags, userTag)
+	}
+	for _, username := range mentions {
+		taggedProfile, err := getProfileByUsernameDto(nextCtx, username)
+		if err != nil {
+			util.Tracer.LogError(span, err)
+			return nil, err
+		}
+		if containsUserTag(userTags, taggedProfile.ProfileId) {
+			continue
+		}
+		if err = canBeTagged(taggedProfile, followingProfiles); err != nil {
+			util.Tracer.LogError(span, err)
+			return nil, err
+		}
+		userTags = append(userTags, model.UserTag{ProfileID: taggedProfile.ProfileId})
+	}
+
+	for i := range userTags {
+		for _, userTag := range current {
+			if userTag.ProfileID == userTags[i].ProfileID {
+				userTags[i].Approved = userTag.Approved
+			}
+		}
+	}
+	return userTags, nil
+}
+
+func canBeTagged(taggedProfile dto.ProfileDto, followingProfiles []uint) error {
+	if !taggedProfile.ProfileSettings.CanBeTagged {
+		return errors.New(taggedProfile.Username + " can't be tagged!")
+	}
+	if !util.Contains(followingProfiles, taggedProfile.ProfileId) {
+		return errors.New(taggedProfile.Username + " is not followed by this profile!")
+	}
+	return nil
+}
+
+func getMentionedUsernames(description string) []string {
+	ret := make([]string, 0)
+	for _, word := range strings.Split(description, " ") {
+		if strings.HasPrefix(word, "@") && len(word) > 1 && !containsString(ret, word[1:]) {
+			ret = append(ret, word[1:])
+		}
+	}
+	return ret
+}
+
+func containsUserTag(userTags []model.UserTag, profileID uint) bool {
+	for _, userTag := range userTags {
+		if userTag.ProfileID == profileID {
+			return true
+		}
+	}
+	return false
+}
+
+func followedProfileIDs(followingProfiles []util.FollowingProfileDTO) []uint {
+	ids := make([]uint, 0)
+	for _, profile := range followingProfiles {
+		ids = append(ids, profile.ProfileID)
+	}
+	return ids
+}
+
+func getProfileByUsernameDto(ctx context.Context, username string) (dto.ProfileDto, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "getProfileByUsernameDto-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	resp, err := getProfileByUsername(nextCtx, username)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.ProfileDto{}, err
+	}
+	return decodeProfile(resp)
+}
+
+func getProfileByID(ctx context.Context, profileID uint) (dto.ProfileDto, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "getProfileByID-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	profileHost, profilePort := util.GetProfileHostAndPort()
+	resp, err := util.CrossServiceRequest(nextCtx, http.MethodGet,
+		util.GetCrossServiceProtocol()+"://"+profileHost+":"+profilePort+"/get-by-id/"+util.Uint2String(profileID),
+		nil, map[string]string{})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.ProfileDto{}, err
+	}
+	return decodeProfile(resp)
+}
+
+func decodeProfile(resp *http.Response) (dto.ProfileDto, error) {
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	var profile dto.ProfileDto
+	if resp.StatusCode != http.StatusOK {
+		return profile, ErrInvalidInput
+	}
+	err := json.NewDecoder(resp.Body).Decode(&profile)
+	return profile, err
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"io"
+	"net/http"
+	"nistagram/post/dto"
+	"nistagram/post/model"
+	"nistagram/post/repository"
+	"nistagram/post/visibility"
+	"nistagram/util"
+	"time"
+)
+
+// A timeline page reads a few times more cached entries than it returns, as some of them may have been deleted or
+// hidden from the viewer since they were pushed.
+const timelineOverfetch = 3
+
+// GetPostsForHomePage reads the chronological home feed from the cached timeline of the logged user, building it
+// first if it is cold. The posts of followed celebrities are not pushed to timelines, they are read with the page.
+func (service *PostService) GetPostsForHomePage(ctx context.Context, followingProfiles []util.FollowingProfileDTO, loggedUserID uint, page model.Page) (dto.PostPageDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetPostsForHomePage-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	policy, err := service.GetVisibilityPolicy(nextCtx, followingProfiles, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostPageDTO{}, err
+	}
+	posts, nextCursor, err := service.getTimelinePosts(nextCtx, policy, followedProfileIDs(followingProfiles), loggedUserID, page)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostPageDTO{}, err
+	}
+	ret, err := service.getReactionsForPosts(nextCtx, posts, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostPageDTO{}, err
+	}
+	if !page.IsFirst() {
+		return dto.PostPageDTO{Posts: ret, NextCursor: nextCursor}, nil
+	}
+	ret, err = service.appendSponsoredPosts(nextCtx, ret, followingProfiles, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostPageDTO{}, err
+	}
+	return dto.PostPageDTO{Posts: ret, NextCursor: nextCursor}, nil
+}
+
+func (service *PostService) getTimelinePosts(ctx context.Context, policy visibility.Policy, followedIDs []uint, loggedUserID uint, page model.Page) ([]model.Post, string, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "getTimelinePosts-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	celebrities, err := service.Timeline.GetCelebrities(nextCtx)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, "", err
+	}
+	celebrityIDs, cachedIDs := make([]uint, 0), make([]uint, 0)
+	for _, id := range followedIDs {
+		if util.Contains(celebrities, id) {
+			celebrityIDs = append(celebrityIDs, id)
+		} else {
+			cachedIDs = append(cachedIDs, id)
+		}
+	}
+
+	warm, err := service.Timeline.IsWarm(nextCtx, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, "", err
+	}
+	if !warm {
+		entries, err := service.PostRepository.GetTimelineEntries(nextCtx, cachedIDs, repository.MaxTimelineLength)
+		if err != nil {
+			util.Tracer.LogError(span, err)
+			return nil, "", err
+		}
+		if err = service.Timeline.Rebuild(nextCtx, loggedUserID, entries); err != nil {
+			util.Tracer.LogError(span, err)
+			return nil, "", err
+		}
+	}
+
+	var publishedBefore *time.Time
+	if page.Cursor != nil {
+		publishedBefore = &page.Cursor.PublishDate
+	}
+	count := page.Limit * timelineOverfetch
+	entries, err := service.Timeline.Read(nextCtx, loggedUserID, publishedBefore, count)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, "", err
+	}
+	postIDs := make([]primitive.ObjectID, 0)
+	for _, entry := range entries {
+		postIDs = append(postIDs, entry.PostID)
+	}
+	var oldest *model.Cursor
+	if len(entries) == count {
+		last := entries[len(entries)-1]
+		oldest = &model.Cursor{PublishDate: last.PublishDate, ID: last.PostID}
+	}
+	return service.PostRepository.GetTimelinePosts(nextCtx, policy, followedIDs, postIDs, celebrityIDs, oldest, page)
+}
+
+// FanOutPost pushes a post that was just published to the timelines of the followers of its publisher, unless the
+// publisher has more followers than the celebrity threshold. The timelines are a cache, so failures are only logged.
+func (service *PostService) FanOutPost(ctx context.Context, post model.Post) {
+	span := util.Tracer.StartSpanFromContext(ctx, "FanOutPost-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	followerIDs, err := getFollowerIDs(nextCtx, post.PublisherId)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return
+	}
+	celebrity := len(followerIDs) > service.CelebrityThreshold
+	if err = service.Timeline.SetCelebrity(nextCtx, post.PublisherId, celebrity); err != nil || celebrity {
+		return
+	}
+	if err = service.Timeline.Push(nextCtx, followerIDs, model.NewTimelineEntry(post)); err != nil {
+		util.Tracer.LogError(span, err)
+	}
+}
+
+// FollowPublisher adds the recent posts of a publisher the profile started following to its timeline.
+func (service *PostService) FollowPublisher(ctx context.Context, profileID uint, publisherID uint) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "FollowPublisher-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	celebrities, err := service.Timeline.GetCelebrities(nextCtx)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if util.Contains(celebrities, publisherID) {
+		return nil
+	}
+	entries, err := service.PostRepository.GetTimelineEntries(nextCtx, []uint{publisherID}, repository.MaxTimelineLength)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	return service.Timeline.Add(nextCtx, profileID, entries)
+}
+
+// UnfollowPublisher takes the posts of a publisher the profile unfollowed, muted or blocked out of its timeline.
+func (service *PostService) UnfollowPublisher(ctx context.Context, profileID uint, publisherID uint) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "UnfollowPublisher-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	return service.Timeline.RemovePublisher(nextCtx, profileID, publisherID)
+}
+
+func getFollowerIDs(ctx context.Context, profileID uint) ([]uint, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "getFollowerIDs-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	connHost, connPort := util.GetConnectionHostAndPort()
+	resp, err := util.CrossServiceRequest(nextCtx, http.MethodGet,
+		util.GetCrossServiceProtocol()+"://"+connHost+":"+connPort+"/connection/followers/show/"+util.Uint2String(profileID),
+		nil, map[string]string{})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		util.Tracer.LogError(span, fmt.Errorf("followers request failed with status %d", resp.StatusCode))
+		return nil, fmt.Errorf("FOLLOWERS_UNAVAILABLE")
+	}
+
+	var followerIDs []uint
+	if err = json.NewDecoder(resp.Body).Decode(&followerIDs); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	return followerIDs, nil
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"net/http"
+	"nistagram/post/dto"
+	"nistagram/post/model"
+	"nistagram/util"
+	"time"
+)
+
+const purgeBatchSize = 100
+
+func (service *PostService) GetTrash(ctx context.Context, loggedUserID uint, page model.Page) (dto.PostPageDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetTrash-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	posts, nextCursor, err := service.PostRepository.GetTrash(nextCtx, loggedUserID, time.Now().Add(-service.TrashRetention), page)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.PostPageDTO{}, err
+	}
+	ret := make([]dto.ResponsePostDTO, 0)
+	for _, post := range posts {
+		ret = append(ret, dto.ResponsePostDTO{Post: post, Reaction: "none", IsEdited: post.IsEdited(),
+			MediaURLs: service.getMediaURLs(nextCtx, post.Medias)})
+	}
+	return dto.PostPageDTO{Posts: ret, NextCursor: nextCursor}, nil
+}
+
+func (service *PostService) DeleteOwnPost(ctx context.Context, loggedUserID uint, id primitive.ObjectID) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "DeleteOwnPost-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	post, err := service.PostRepository.Read(nextCtx, id)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if post.PublisherId != loggedUserID {
+		util.Tracer.LogError(span, fmt.Errorf("profile %d is not the publisher of post %s", loggedUserID, id.Hex()))
+		return ErrForbidden
+	}
+	return service.DeletePost(nextCtx, id, loggedUserID)
+}
+
+// RemovePost moves a reported post to the trash on behalf of a moderator, so its publisher can not restore it.
+// Moderation closes the reports of the post itself.
+func (service *PostService) RemovePost(ctx context.Context, moderatorID uint, id primitive.ObjectID) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "RemovePost-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	if moderatorID == 0 {
+		util.Tracer.LogError(span, fmt.Errorf("post %s removed without a moderator", id.Hex()))
+		return ErrInvalidInput
+	}
+	return service.trashPost(nextCtx, id, moderatorID)
+}
+
+// RestorePost takes a post out of the trash, as long as its publisher deleted it within the retention period.
+func (service *PostService) RestorePost(ctx context.Context, loggedUserID uint, id primitive.ObjectID) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "RestorePost-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	post, err := service.PostRepository.Restore(nextCtx, id, loggedUserID, time.Now().Add(-service.TrashRetention))
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if !post.IsPublished() {
+		return nil
+	}
+	service.FanOutPost(nextCtx, post)
+	return service.PostRepository.UpdateHashTagCounts(nextCtx, post.Tags, 1, post.PublishDate)
+}
+
+func (service *PostService) BackfillDeletedAt(ctx context.Context) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "BackfillDeletedAt-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	return service.PostRepository.BackfillDeletedAt(nextCtx, time.Now())
+}
+
+// PurgeDeletedPosts hard-deletes posts that have been in the trash longer than the retention period, together with
+// their media and everything the postreaction service keeps about them. A post whose reactions could not be removed
+// stays in the trash and is retried on the next run.
+func (service *PostService) PurgeDeletedPosts(ctx context.Context) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "PurgeDeletedPosts-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	posts, err := service.PostRepository.GetPostsDeletedBefore(nextCtx, time.Now().Add(-service.TrashRetention), purgeBatchSize)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	for _, post := range posts {
+		if err = deletePostData(nextCtx, post.ID); err != nil {
+			util.Tracer.LogError(span, err)
+			continue
+		}
+		if err = service.PostRepository.Purge(nextCtx, post.ID); err != nil {
+			util.Tracer.LogError(span, err)
+			continue
+		}
+		service.DeleteMedia(nextCtx, post.Medias)
+	}
+	return nil
+}
+
+func deletePostData(ctx context.Context, postId primitive.ObjectID) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "deletePostData-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	postReactionHost, postReactionPort := util.GetPostReactionHostAndPort()
+	resp, err := util.CrossServiceRequest(nextCtx, http.MethodDelete,
+		util.GetCrossServiceProtocol()+"://"+postReactionHost+":"+postReactionPort+"/post/"+util.GetStringIDFromMongoID(postId),
+		nil, map[string]string{})
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("postreaction responded with %d", resp.StatusCode)
+	}
+	return nil
+}
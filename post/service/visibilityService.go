@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"nistagram/post/dto"
+	"nistagram/post/model"
+	"nistagram/post/visibility"
+	"nistagram/util"
+	"time"
+)
+
+// GetVisibilityPolicy builds the policy posts are read with on behalf of the logged user; anonymous users have the
+// ID 0 and follow no one.
+func (service *PostService) GetVisibilityPolicy(ctx context.Context, followingProfiles []util.FollowingProfileDTO, loggedUserID uint) (visibility.Policy, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetVisibilityPolicy-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	blockedRelationships := make([]uint, 0)
+	if loggedUserID != 0 {
+		var err error
+		blockedRelationships, err = getProfilesBlockedRelationships(nextCtx, loggedUserID)
+		if err != nil {
+			util.Tracer.LogError(span, err)
+			return visibility.Policy{}, err
+		}
+	}
+	return visibility.NewPolicy(loggedUserID, followingProfiles, blockedRelationships, time.Now()), nil
+}
+
+// GetPublicPolicy is the policy of public listings, like the public feed and the hashtag and location results; they
+// leave out sensitive posts unless the logged user opted in to see them.
+func (service *PostService) GetPublicPolicy(ctx context.Context, loggedUserID uint) (visibility.Policy, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetPublicPolicy-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	policy, err := service.GetVisibilityPolicy(nextCtx, nil, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return policy, err
+	}
+	showSensitive, err := showsSensitiveContent(nextCtx, loggedUserID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return visibility.Policy{}, err
+	}
+	if showSensitive {
+		policy = policy.WithSensitiveContent()
+	}
+	return policy, nil
+}
+
+// ReadPost reads a post the policy lets the viewer see; posts that are hidden are reported as missing, so their
+// existence is not revealed.
+func (service *PostService) ReadPost(ctx context.Context, policy visibility.Policy, id primitive.ObjectID) (model.Post, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "ReadPost-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	post, err := service.PostRepository.Read(nextCtx, id)
+	if err != nil {
+		return post, err
+	}
+	if !policy.CanSee(post) {
+		util.Tracer.LogError(span, fmt.Errorf("post %s is not visible to profile %d", id.Hex(), policy.ViewerID()))
+		return model.Post{}, mongo.ErrNoDocuments
+	}
+	return post, nil
+}
+
+// setContentWarnings adds a content warning to the sensitive posts; their media is blurred, unless the viewer
+// published the post or opted in to see sensitive content.
+func setContentWarnings(ctx context.Context, posts []dto.ResponsePostDTO, profileID uint) {
+	span := util.Tracer.StartSpanFromContext(ctx, "setContentWarnings-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	var showSensitive *bool
+	for i := range posts {
+		post := posts[i].Post
+		if !post.IsSensitive() {
+			continue
+		}
+		if showSensitive == nil {
+			show, err := showsSensitiveContent(nextCtx, profileID)
+			if err != nil {
+				util.Tracer.LogError(span, err)
+			}
+			showSensitive = &show
+		}
+		blur := !*showSensitive && post.PublisherId != profileID
+		posts[i].ContentWarning = &dto.ContentWarningDTO{Category: post.SensitiveCategory.ToString(), Blur: blur}
+	}
+}
+
+// showsSensitiveContent reads the content preference of the profile; anonymous viewers have not opted in.
+func showsSensitiveContent(ctx context.Context, profileID uint) (bool, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "showsSensitiveContent-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	if profileID == 0 {
+		return false, nil
+	}
+	profile, err := getProfileByID(nextCtx, profileID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return false, err
+	}
+	return profile.ProfileSettings.ShowSensitiveContent, nil
+}
@@ -0,0 +1,145 @@
+package visibility
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"nistagram/post/model"
+	"nistagram/util"
+	"time"
+)
+
+// Policy decides which posts a viewer can see. The same rules back CanSee, for single posts, and Filter, for list
+// queries, so the two can not drift apart:
+//   - deleted posts are never visible;
+//   - the publisher sees all of their own posts, drafts and scheduled posts included;
+//   - everyone else sees only published posts of profiles they are not in a block relationship with;
+//   - posts of private profiles are only visible to followers;
+//   - close friends only posts are only visible to close friends;
+//   - stories are visible until they expire, or for good once they are highlighted.
+type Policy struct {
+	viewerID      uint
+	followed      []uint
+	closeFriends  []uint
+	blocked       []uint
+	now           time.Time
+	service       bool
+	showSensitive bool
+}
+
+// NewPolicy builds the policy of a logged user; an anonymous viewer has the ID 0 and no following profiles.
+func NewPolicy(viewerID uint, followingProfiles []util.FollowingProfileDTO, blockedRelationships []uint, now time.Time) Policy {
+	policy := Policy{viewerID: viewerID, followed: make([]uint, 0), closeFriends: make([]uint, 0),
+		blocked: make([]uint, 0), now: now}
+	for _, profile := range followingProfiles {
+		policy.followed = append(policy.followed, profile.ProfileID)
+		if profile.CloseFriend {
+			policy.closeFriends = append(policy.closeFriends, profile.ProfileID)
+		}
+	}
+	policy.blocked = append(policy.blocked, blockedRelationships...)
+	return policy
+}
+
+// ForService is used when another service reads posts on its own behalf, like moderation and campaigns; it only
+// hides deleted posts.
+func ForService() Policy {
+	return Policy{service: true}
+}
+
+// WithSensitiveContent is the policy of a viewer who opted in to see sensitive posts in public listings.
+func (policy Policy) WithSensitiveContent() Policy {
+	policy.showSensitive = true
+	return policy
+}
+
+// ShowsSensitiveContent tells whether public listings include sensitive posts; other listings always do, behind a
+// content warning.
+func (policy Policy) ShowsSensitiveContent() bool {
+	return policy.service || policy.showSensitive
+}
+
+func (policy Policy) ViewerID() uint {
+	return policy.viewerID
+}
+
+// IsBlocked tells whether the viewer and the profile are in a block relationship.
+func (policy Policy) IsBlocked(profileID uint) bool {
+	return !policy.service && util.Contains(policy.blocked, profileID)
+}
+
+func (policy Policy) Follows(profileID uint) bool {
+	return policy.service || policy.isViewer(profileID) || util.Contains(policy.followed, profileID)
+}
+
+func (policy Policy) CanSee(post model.Post) bool {
+	return policy.canSee(post, true)
+}
+
+// CanSeeInHighlight is CanSee for a story shown through a highlight, which keeps it visible after it expired and was
+// moved to the story archive.
+func (policy Policy) CanSeeInHighlight(story model.Post) bool {
+	return policy.canSee(story, false)
+}
+
+func (policy Policy) canSee(post model.Post, checkExpiry bool) bool {
+	if post.IsDeleted {
+		return false
+	}
+	if policy.service || policy.isViewer(post.PublisherId) {
+		return true
+	}
+	if !post.IsPublished() || util.Contains(policy.blocked, post.PublisherId) {
+		return false
+	}
+	if post.IsPrivate && !util.Contains(policy.followed, post.PublisherId) {
+		return false
+	}
+	if post.IsCloseFriendsOnly && !util.Contains(policy.closeFriends, post.PublisherId) {
+		return false
+	}
+	if checkExpiry && post.PostType == model.STORY && !post.IsHighlighted && !post.ExpiresAt.After(policy.now) {
+		return false
+	}
+	return true
+}
+
+// Filter matches the posts the viewer can see.
+func (policy Policy) Filter() bson.D {
+	return policy.FilterAt("")
+}
+
+// FilterAt is Filter for posts embedded under the given path, like the ones joined into an aggregation; the path
+// ends with a dot.
+func (policy Policy) FilterAt(path string) bson.D {
+	notDeleted := bson.D{{path + "isdeleted", false}}
+	if policy.service {
+		return notDeleted
+	}
+	others := bson.D{{"$and", bson.A{
+		bson.D{{path + "status", bson.D{{"$nin", bson.A{model.DRAFT, model.SCHEDULED}}}}},
+		bson.D{{path + "publisherid", bson.D{{"$nin", policy.blocked}}}},
+		bson.D{{"$or", bson.A{
+			bson.D{{path + "isprivate", false}},
+			bson.D{{path + "publisherid", bson.D{{"$in", policy.followed}}}},
+		}}},
+		bson.D{{"$or", bson.A{
+			bson.D{{path + "isclosefriendsonly", false}},
+			bson.D{{path + "publisherid", bson.D{{"$in", policy.closeFriends}}}},
+		}}},
+		bson.D{{"$or", bson.A{
+			bson.D{{path + "posttype", bson.D{{"$ne", model.STORY}}}},
+			bson.D{{path + "ishighlighted", true}},
+			bson.D{{path + "expiresat", bson.D{{"$gt", policy.now}}}},
+		}}},
+	}}}
+	if policy.viewerID == 0 {
+		return bson.D{{"$and", bson.A{notDeleted, others}}}
+	}
+	return bson.D{{"$and", bson.A{notDeleted, bson.D{{"$or", bson.A{
+		bson.D{{path + "publisherid", policy.viewerID}},
+		others,
+	}}}}}}
+}
+
+func (policy Policy) isViewer(profileID uint) bool {
+	return policy.viewerID != 0 && policy.viewerID == profileID
+}
@@ -0,0 +1,312 @@
+package visibility
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"nistagram/post/model"
+	"nistagram/util"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	viewer      uint = 1
+	closeFriend uint = 2
+	followed    uint = 3
+	blocked     uint = 4
+	stranger    uint = 5
+)
+
+var now = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func loggedPolicy() Policy {
+	following := []util.FollowingProfileDTO{{ProfileID: closeFriend, CloseFriend: true}, {ProfileID: followed}}
+	return NewPolicy(viewer, following, []uint{blocked}, now)
+}
+
+func anonymousPolicy() Policy {
+	return NewPolicy(0, nil, nil, now)
+}
+
+func post(publisherID uint, change func(post *model.Post)) model.Post {
+	post := model.Post{ID: primitive.NewObjectID(), PublisherId: publisherID, PostType: model.POST,
+		PublishDate: now.Add(-time.Hour), Status: model.PUBLISHED}
+	if change != nil {
+		change(&post)
+	}
+	return post
+}
+
+func story(publisherID uint, expiresAt time.Time, change func(post *model.Post)) model.Post {
+	return post(publisherID, func(post *model.Post) {
+		post.PostType = model.STORY
+		post.ExpiresAt = expiresAt
+		if change != nil {
+			change(post)
+		}
+	})
+}
+
+var (
+	deleted          = func(post *model.Post) { post.IsDeleted = true }
+	draft            = func(post *model.Post) { post.Status = model.DRAFT }
+	scheduled        = func(post *model.Post) { post.Status = model.SCHEDULED }
+	private          = func(post *model.Post) { post.IsPrivate = true }
+	closeFriendsOnly = func(post *model.Post) { post.IsCloseFriendsOnly = true }
+	highlighted      = func(post *model.Post) { post.IsHighlighted = true }
+)
+
+func TestPolicy(t *testing.T) {
+	live := now.Add(time.Hour)
+	expired := now.Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		policy  Policy
+		post    model.Post
+		visible bool
+	}{
+		{"public post to anonymous viewer", anonymousPolicy(), post(stranger, nil), true},
+		{"public post to logged viewer", loggedPolicy(), post(stranger, nil), true},
+		{"deleted post", loggedPolicy(), post(followed, deleted), false},
+		{"own deleted post", loggedPolicy(), post(viewer, deleted), false},
+		{"own draft", loggedPolicy(), post(viewer, draft), true},
+		{"own scheduled post", loggedPolicy(), post(viewer, scheduled), true},
+		{"draft of followed profile", loggedPolicy(), post(followed, draft), false},
+		{"scheduled post of followed profile", loggedPolicy(), post(followed, scheduled), false},
+		{"post of blocked profile", loggedPolicy(), post(blocked, nil), false},
+		{"post of blocked profile to anonymous viewer", anonymousPolicy(), post(blocked, nil), true},
+		{"own private post", loggedPolicy(), post(viewer, private), true},
+		{"private post of followed profile", loggedPolicy(), post(followed, private), true},
+		{"private post of stranger", loggedPolicy(), post(stranger, private), false},
+		{"private post to anonymous viewer", anonymousPolicy(), post(followed, private), false},
+		{"close friends post to close friend", loggedPolicy(), post(closeFriend, closeFriendsOnly), true},
+		{"close friends post to follower", loggedPolicy(), post(followed, closeFriendsOnly), false},
+		{"close friends post to anonymous viewer", anonymousPolicy(), post(stranger, closeFriendsOnly), false},
+		{"own close friends post", loggedPolicy(), post(viewer, closeFriendsOnly), true},
+		{"close friends story to close friend", loggedPolicy(), story(closeFriend, live, closeFriendsOnly), true},
+		{"close friends story to follower", loggedPolicy(), story(followed, live, closeFriendsOnly), false},
+		{"live story", loggedPolicy(), story(followed, live, nil), true},
+		{"expired story", loggedPolicy(), story(followed, expired, nil), false},
+		{"story expiring now", loggedPolicy(), story(followed, now, nil), false},
+		{"expired highlighted story", loggedPolicy(), story(followed, expired, highlighted), true},
+		{"own expired story", loggedPolicy(), story(viewer, expired, nil), true},
+		{"private live story of stranger", loggedPolicy(), story(stranger, live, private), false},
+		{"private draft of stranger to service", ForService(), post(stranger, func(post *model.Post) {
+			private(post)
+			draft(post)
+		}), true},
+		{"expired story to service", ForService(), story(stranger, expired, nil), true},
+		{"deleted post to service", ForService(), post(stranger, deleted), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if visible := test.policy.CanSee(test.post); visible != test.visible {
+				t.Errorf("CanSee() = %v, want %v", visible, test.visible)
+			}
+			document := toDocument(t, test.post)
+			if matched := matches(toFilter(t, test.policy.Filter()), document); matched != test.visible {
+				t.Errorf("Filter() matched = %v, want %v", matched, test.visible)
+			}
+			embedded := bson.M{"post": document}
+			if matched := matches(toFilter(t, test.policy.FilterAt("post.")), embedded); matched != test.visible {
+				t.Errorf("FilterAt() matched = %v, want %v", matched, test.visible)
+			}
+		})
+	}
+}
+
+func TestPolicyCanSeeInHighlight(t *testing.T) {
+	archived := now.Add(-48 * time.Hour)
+
+	tests := []struct {
+		name    string
+		policy  Policy
+		story   model.Post
+		visible bool
+	}{
+		{"archived story", loggedPolicy(), story(followed, archived, nil), true},
+		{"archived story to anonymous viewer", anonymousPolicy(), story(stranger, archived, nil), true},
+		{"archived private story of stranger", loggedPolicy(), story(stranger, archived, private), false},
+		{"archived close friends story to follower", loggedPolicy(), story(followed, archived, closeFriendsOnly), false},
+		{"archived story of blocked profile", loggedPolicy(), story(blocked, archived, nil), false},
+		{"deleted story", loggedPolicy(), story(followed, archived, deleted), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if visible := test.policy.CanSeeInHighlight(test.story); visible != test.visible {
+				t.Errorf("CanSeeInHighlight() = %v, want %v", visible, test.visible)
+			}
+		})
+	}
+}
+
+func TestPolicyProfileChecks(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    Policy
+		profileID uint
+		blocked   bool
+		follows   bool
+	}{
+		{"own profile", loggedPolicy(), viewer, false, true},
+		{"followed profile", loggedPolicy(), followed, false, true},
+		{"blocked profile", loggedPolicy(), blocked, true, false},
+		{"stranger", loggedPolicy(), stranger, false, false},
+		{"anonymous viewer", anonymousPolicy(), stranger, false, false},
+		{"service", ForService(), blocked, false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if isBlocked := test.policy.IsBlocked(test.profileID); isBlocked != test.blocked {
+				t.Errorf("IsBlocked() = %v, want %v", isBlocked, test.blocked)
+			}
+			if follows := test.policy.Follows(test.profileID); follows != test.follows {
+				t.Errorf("Follows() = %v, want %v", follows, test.follows)
+			}
+		})
+	}
+}
+
+func TestPolicyShowsSensitiveContent(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		shows  bool
+	}{
+		{"logged viewer", loggedPolicy(), false},
+		{"logged viewer who opted in", loggedPolicy().WithSensitiveContent(), true},
+		{"anonymous viewer", anonymousPolicy(), false},
+		{"service", ForService(), true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if shows := test.policy.ShowsSensitiveContent(); shows != test.shows {
+				t.Errorf("ShowsSensitiveContent() = %v, want %v", shows, test.shows)
+			}
+		})
+	}
+}
+
+// toDocument and toFilter round trip through BSON, so both sides of a comparison hold the types Mongo would see.
+func toDocument(t *testing.T, post model.Post) bson.M {
+	data, err := bson.Marshal(post)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var document bson.M
+	if err = bson.Unmarshal(data, &document); err != nil {
+		t.Fatal(err)
+	}
+	return document
+}
+
+func toFilter(t *testing.T, filter bson.D) bson.D {
+	data, err := bson.Marshal(filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ret bson.D
+	if err = bson.Unmarshal(data, &ret); err != nil {
+		t.Fatal(err)
+	}
+	return ret
+}
+
+// matches evaluates the subset of the Mongo query language the policy uses.
+func matches(filter bson.D, document bson.M) bool {
+	for _, element := range filter {
+		switch element.Key {
+		case "$and":
+			for _, condition := range element.Value.(bson.A) {
+				if !matches(condition.(bson.D), document) {
+					return false
+				}
+			}
+		case "$or":
+			matched := false
+			for _, condition := range element.Value.(bson.A) {
+				matched = matched || matches(condition.(bson.D), document)
+			}
+			if !matched {
+				return false
+			}
+		default:
+			value, found := lookup(document, element.Key)
+			if !matchesField(value, found, element.Value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func matchesField(value interface{}, found bool, condition interface{}) bool {
+	operators, ok := condition.(bson.D)
+	if !ok {
+		return found && equal(value, condition)
+	}
+	for _, operator := range operators {
+		switch operator.Key {
+		case "$in":
+			if !found || !contains(operator.Value.(bson.A), value) {
+				return false
+			}
+		case "$nin":
+			if found && contains(operator.Value.(bson.A), value) {
+				return false
+			}
+		case "$ne":
+			if found && equal(value, operator.Value) {
+				return false
+			}
+		case "$gt":
+			if !found || value.(primitive.DateTime) <= operator.Value.(primitive.DateTime) {
+				return false
+			}
+		default:
+			panic("unsupported operator " + operator.Key)
+		}
+	}
+	return true
+}
+
+func lookup(document bson.M, path string) (interface{}, bool) {
+	var value interface{} = document
+	for _, key := range strings.Split(path, ".") {
+		current, ok := value.(bson.M)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = current[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func contains(values bson.A, value interface{}) bool {
+	for _, item := range values {
+		if equal(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func equal(a interface{}, b interface{}) bool {
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
+
+func normalize(value interface{}) interface{} {
+	if number, ok := value.(int32); ok {
+		return int64(number)
+	}
+	return value
+}
@@ -2,6 +2,7 @@ package dto
 
 type CommentDTO struct {
 	PostID  	   string `json:"postId"`
+	ParentID  	   string `json:"parentId"`
 	Content 	   string `json:"content"`
 	CampaignID     uint `json:"campaignId"`
 	InfluencerID   uint `json:"influencerID"`
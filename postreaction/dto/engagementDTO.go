@@ -0,0 +1,21 @@
+package dto
+
+type EngagementDTO struct {
+	PostID   string `json:"postId"`
+	Likes    int    `json:"likes"`
+	Comments int    `json:"comments"`
+}
+
+// InteractionDTO counts how many times a profile liked or commented a post.
+type InteractionDTO struct {
+	PostID string `json:"postId"`
+	Count  int    `json:"count"`
+}
+
+// DailyEngagementDTO counts the reactions and comments a post got in a day.
+type DailyEngagementDTO struct {
+	Day      string `json:"day"`
+	Likes    int    `json:"likes"`
+	Dislikes int    `json:"dislikes"`
+	Comments int    `json:"comments"`
+}
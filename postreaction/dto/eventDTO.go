@@ -7,4 +7,5 @@ type EventDTO struct {
 	CampaignId		uint		`json:"campaignId"`
 	InfluencerId	uint		`json:"influencerId"`
 	InfluencerUsername	string	`json:"influencerUsername"`
+	Reaction		string		`json:"reaction"`
 }
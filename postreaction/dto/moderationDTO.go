@@ -0,0 +1,58 @@
+package dto
+
+import "time"
+
+// ReportGroupDTO is a target in the moderation queue with its open reports. Post is set for reported posts that
+// still exist.
+type ReportGroupDTO struct {
+	TargetType    string             `json:"targetType"`
+	TargetID      string             `json:"targetId"`
+	TargetOwnerID uint               `json:"targetOwnerId"`
+	OwnerUsername string             `json:"ownerUsername"`
+	Status        string             `json:"status"`
+	ReviewerID    uint               `json:"reviewerId"`
+	ReportCount   int                `json:"reportCount"`
+	Categories    map[string]int     `json:"categories"`
+	FirstReported time.Time          `json:"firstReported"`
+	LastReported  time.Time          `json:"lastReported"`
+	Reports       []ReportSummaryDTO `json:"reports"`
+	Post          *PostDTO           `json:"post,omitempty"`
+}
+
+type ReportSummaryDTO struct {
+	ID         string       `json:"id"`
+	ReporterID uint         `json:"reporterId"`
+	Category   string       `json:"category"`
+	Reason     string       `json:"reason"`
+	Evidence   *EvidenceDTO `json:"evidence,omitempty"`
+	Time       time.Time    `json:"time"`
+}
+
+type EvidenceDTO struct {
+	Text      string    `json:"text"`
+	MediaPath string    `json:"mediaPath"`
+	PostID    string    `json:"postId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ModerationNoteDTO is what a moderator writes down about an action; warnings are sent to the owner with it.
+type ModerationNoteDTO struct {
+	Note string `json:"note"`
+}
+
+type ModerationLogEntryDTO struct {
+	ID            string    `json:"id"`
+	ModeratorID   uint      `json:"moderatorId"`
+	Action        string    `json:"action"`
+	TargetType    string    `json:"targetType"`
+	TargetID      string    `json:"targetId"`
+	TargetOwnerID uint      `json:"targetOwnerId"`
+	ReportIDs     []string  `json:"reportIds"`
+	Note          string    `json:"note"`
+	Time          time.Time `json:"time"`
+}
+
+type ModerationLogPageDTO struct {
+	Entries    []ModerationLogEntryDTO `json:"entries"`
+	NextCursor string                  `json:"nextCursor,omitempty"`
+}
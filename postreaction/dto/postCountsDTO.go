@@ -0,0 +1,9 @@
+package dto
+
+// PostCountsDTO counts the reactions of each type and the comments of a post, with the reaction the viewer left on it.
+type PostCountsDTO struct {
+	PostID    string         `json:"postId"`
+	Reactions map[string]int `json:"reactions"`
+	Comments  int            `json:"comments"`
+	Reaction  string         `json:"reaction"`
+}
@@ -12,3 +12,6 @@ type PostDTO struct {
 	Description        string    `json:"description"`
 }
 
+type Media struct {
+	FilePath string `json:"filePath"`
+}
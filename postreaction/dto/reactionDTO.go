@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type ReactionDTO struct {
 	PostID         		 string `json:"postId"`
 	ReactionType   		 string `json:"reactionType"`
@@ -7,3 +9,21 @@ type ReactionDTO struct {
 	InfluencerID   		 uint `json:"influencerID"`
 	InfluencerUsername   string `json:"influencerUsername"`
 }
+
+// ReactionCountDTO is how many profiles left a reaction on a post.
+type ReactionCountDTO struct {
+	Type  string `json:"type"`
+	Emoji string `json:"emoji"`
+	Count int    `json:"count"`
+}
+
+type ReactorDTO struct {
+	ProfileID uint      `json:"profileId"`
+	Username  string    `json:"username"`
+	Time      time.Time `json:"time"`
+}
+
+type ReactorPageDTO struct {
+	Reactors   []ReactorDTO `json:"reactors"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+}
@@ -1,6 +1,10 @@
 package dto
 
+// ReportDTO files a report about a target of a type. Older clients report posts by PostID only.
 type ReportDTO struct {
-	PostID string `json:"postId"`
-	Reason string `json:"reason"`
+	TargetType string `json:"targetType"`
+	TargetID   string `json:"targetId"`
+	PostID     string `json:"postId"`
+	Category   string `json:"category"`
+	Reason     string `json:"reason"`
 }
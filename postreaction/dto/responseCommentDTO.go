@@ -1,6 +1,19 @@
 package dto
 
+import "time"
+
 type ResponseCommentDTO struct {
-	Content  string `json:"content"`
-	Username string `json:"username"`
+	ID         string    `json:"id"`
+	ParentID   string    `json:"parentId,omitempty"`
+	ProfileID  uint      `json:"profileId"`
+	Username   string    `json:"username"`
+	Content    string    `json:"content"`
+	Time       time.Time `json:"time"`
+	IsEdited   bool      `json:"isEdited"`
+	ReplyCount int       `json:"replyCount"`
+}
+
+type CommentPageDTO struct {
+	Comments   []ResponseCommentDTO `json:"comments"`
+	NextCursor string               `json:"nextCursor"`
 }
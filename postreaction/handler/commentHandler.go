@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"net/http"
+	postModel "nistagram/post/model"
+	"nistagram/postreaction/service"
+	"nistagram/util"
+	"strconv"
+)
+
+func (handler *PostReactionHandler) GetReplies(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("GetReplies-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["commentID"])
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	page, err := getPage(r)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	replies, err := handler.PostReactionService.GetReplies(ctx, id, page)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(replies)
+}
+
+func (handler *PostReactionHandler) EditComment(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("EditComment-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["commentID"])
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var input struct {
+		Content string `json:"content"`
+	}
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err = handler.PostReactionService.EditComment(ctx, util.GetLoggedUserIDFromToken(r), id, input.Content); err != nil {
+		util.Tracer.LogError(span, err)
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("{\"success\":\"ok\"}"))
+}
+
+func (handler *PostReactionHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("DeleteComment-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	w.Header().Set("Content-Type", "application/json")
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["commentID"])
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err = handler.PostReactionService.DeleteComment(ctx, util.GetLoggedUserIDFromToken(r), id); err != nil {
+		util.Tracer.LogError(span, err)
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("{\"success\":\"ok\"}"))
+}
+
+func getPage(r *http.Request) (postModel.Page, error) {
+	limit := postModel.DefaultPageSize
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			return postModel.Page{}, fmt.Errorf("invalid page size")
+		}
+		if parsed > postModel.MaxPageSize {
+			parsed = postModel.MaxPageSize
+		}
+		limit = parsed
+	}
+	cursor, err := postModel.DecodeCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		return postModel.Page{}, err
+	}
+	return postModel.Page{Cursor: cursor, Limit: limit}, nil
+}
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch err {
+	case mongo.ErrNoDocuments:
+		w.WriteHeader(http.StatusNotFound)
+	case service.ErrForbidden:
+		w.WriteHeader(http.StatusForbidden)
+	case service.ErrInvalidInput:
+		w.WriteHeader(http.StatusBadRequest)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
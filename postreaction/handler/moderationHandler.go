@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"net/http"
+	"nistagram/postreaction/dto"
+	"nistagram/postreaction/model"
+	"nistagram/util"
+)
+
+func (handler *PostReactionHandler) GetModerationQueue(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("GetModerationQueue-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	queue, err := handler.PostReactionService.GetModerationQueue(ctx)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		writeServiceError(w, err)
+		return
+	}
+	js, err := json.Marshal(queue)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(js)
+}
+
+func (handler *PostReactionHandler) GetModerationLog(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("GetModerationLog-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	page, err := getPage(r)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	targetType := model.NO_TARGET
+	targetID := r.URL.Query().Get("targetId")
+	if value := r.URL.Query().Get("targetType"); value != "" {
+		targetType = model.GetTargetType(value)
+		if targetType == model.NO_TARGET || targetID == "" {
+			util.Tracer.LogError(span, fmt.Errorf("bad target in request"))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+	var moderatorID uint
+	if value := r.URL.Query().Get("moderatorId"); value != "" {
+		moderatorID = util.String2Uint(value)
+	}
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	entries, err := handler.PostReactionService.GetModerationLog(ctx, targetType, targetID, moderatorID, page)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		writeServiceError(w, err)
+		return
+	}
+	js, err := json.Marshal(entries)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(js)
+}
+
+func (handler *PostReactionHandler) ReviewReports(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("ReviewReports-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	targetType, targetID, note, err := readModerationRequest(r)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	err = handler.PostReactionService.ReviewReports(ctx, util.GetLoggedUserIDFromToken(r), targetType, targetID, note)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("{\"success\":\"ok\"}"))
+}
+
+func (handler *PostReactionHandler) RemoveTarget(w http.ResponseWriter, r *http.Request) {
+	handler.moderate(w, r, model.REMOVE)
+}
+
+func (handler *PostReactionHandler) WarnOwner(w http.ResponseWriter, r *http.Request) {
+	handler.moderate(w, r, model.WARN)
+}
+
+func (handler *PostReactionHandler) BanOwner(w http.ResponseWriter, r *http.Request) {
+	handler.moderate(w, r, model.BAN)
+}
+
+func (handler *PostReactionHandler) DismissReports(w http.ResponseWriter, r *http.Request) {
+	handler.moderate(w, r, model.DISMISS)
+}
+
+func (handler *PostReactionHandler) moderate(w http.ResponseWriter, r *http.Request, action model.ModerationAction) {
+	span := util.Tracer.StartSpanFromRequest("Moderate-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	targetType, targetID, note, err := readModerationRequest(r)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	err = handler.PostReactionService.Moderate(ctx, util.GetLoggedUserIDFromToken(r), targetType, targetID, action, note)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("{\"success\":\"ok\"}"))
+}
+
+// readModerationRequest reads the target from the path and the optional note from the body.
+func readModerationRequest(r *http.Request) (model.TargetType, string, string, error) {
+	vars := mux.Vars(r)
+	targetType := model.GetTargetType(vars["targetType"])
+	if targetType == model.NO_TARGET {
+		return targetType, "", "", fmt.Errorf("bad target type in request")
+	}
+	var noteDTO dto.ModerationNoteDTO
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&noteDTO); err != nil {
+			return targetType, "", "", err
+		}
+	}
+	return targetType, vars["targetID"], noteDTO.Note, nil
+}
@@ -11,6 +11,7 @@ import (
 	"nistagram/postreaction/service"
 	"nistagram/util"
 	"strings"
+	"time"
 )
 
 type PostReactionHandler struct {
@@ -100,8 +101,8 @@ func (handler *PostReactionHandler) CommentPost(w http.ResponseWriter, r *http.R
 	w.Header().Set("Content-Type", "application/json")
 }
 
-func (handler *PostReactionHandler) ReportPost(w http.ResponseWriter, r *http.Request) {
-	span := util.Tracer.StartSpanFromRequest("ReportPost-handler", r)
+func (handler *PostReactionHandler) Report(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("Report-handler", r)
 	defer util.Tracer.FinishSpan(span)
 	var reportDTO dto.ReportDTO
 	err := json.NewDecoder(r.Body).Decode(&reportDTO)
@@ -112,11 +113,11 @@ func (handler *PostReactionHandler) ReportPost(w http.ResponseWriter, r *http.Re
 		return
 	}
 	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
-	err = handler.PostReactionService.ReportPost(ctx, reportDTO.PostID, reportDTO.Reason)
+	err = handler.PostReactionService.Report(ctx, reportDTO, util.GetLoggedUserIDFromToken(r))
 	if err != nil {
 		util.Tracer.LogError(span, err)
 		fmt.Println(err)
-		w.WriteHeader(http.StatusBadRequest)
+		writeServiceError(w, err)
 		return
 	}
 	w.WriteHeader(http.StatusOK)
@@ -155,48 +156,45 @@ func (handler *PostReactionHandler) GetMyReactions(w http.ResponseWriter, r *htt
 	_, _ = w.Write(js)
 }
 
-func (handler *PostReactionHandler) GetReactionTypes(w http.ResponseWriter, r *http.Request) {
-	span := util.Tracer.StartSpanFromRequest("GetReactionTypes-handler", r)
+func (handler *PostReactionHandler) GetPostCounts(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("GetPostCounts-handler", r)
 	defer util.Tracer.FinishSpan(span)
-	params := mux.Vars(r)
-	profileID := util.String2Uint(params["profileID"])
+
 	type data struct {
 		Ids []string `json:"ids"`
 	}
 	var input data
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		util.Tracer.LogError(span, err)
-		fmt.Println(err)
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
-	ret := handler.PostReactionService.GetReactionTypes(ctx, profileID, input.Ids)
-	js, err := json.Marshal(ret)
+	counts, err := handler.PostReactionService.GetPostCounts(ctx, input.Ids, util.String2Uint(r.URL.Query().Get("viewer")))
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	js, err := json.Marshal(counts)
 	if err != nil {
 		util.Tracer.LogError(span, err)
-		fmt.Println(err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write(js)
 }
 
-func (handler *PostReactionHandler) GetAllReports(w http.ResponseWriter, r *http.Request) {
-	span := util.Tracer.StartSpanFromRequest("GetAllReports-handler", r)
+func (handler *PostReactionHandler) DeletePostsReports(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("DeletePostsReports-handler", r)
 	defer util.Tracer.FinishSpan(span)
 
+	vars := mux.Vars(r)
+	postId := vars["postId"]
 	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
-	reports, err := handler.PostReactionService.GetAllReports(ctx)
-	if err != nil {
-		util.Tracer.LogError(span, err)
-		fmt.Println(err)
-		w.WriteHeader(http.StatusInternalServerError)
-		_, _ = w.Write([]byte("{\"message\":\"error\"}"))
-		return
-	}
-	js, err := json.Marshal(reports)
+	err := handler.PostReactionService.CloseRemovedPostReports(ctx, postId)
 	if err != nil {
 		util.Tracer.LogError(span, err)
 		fmt.Println(err)
@@ -205,18 +203,17 @@ func (handler *PostReactionHandler) GetAllReports(w http.ResponseWriter, r *http
 		return
 	}
 	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write(js)
+	_, _ = w.Write([]byte("{\"message\":\"ok\"}"))
 	w.Header().Set("Content-Type", "application/json")
 }
 
-func (handler *PostReactionHandler) DeletePostsReports(w http.ResponseWriter, r *http.Request) {
-	span := util.Tracer.StartSpanFromRequest("DeletePostsReports-handler", r)
+func (handler *PostReactionHandler) DeletePostData(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("DeletePostData-handler", r)
 	defer util.Tracer.FinishSpan(span)
 
 	vars := mux.Vars(r)
-	postId := vars["postId"]
 	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
-	err := handler.PostReactionService.DeletePostsReports(ctx, postId)
+	err := handler.PostReactionService.DeletePostData(ctx, vars["postId"])
 	if err != nil {
 		util.Tracer.LogError(span, err)
 		fmt.Println(err)
@@ -234,19 +231,14 @@ func (handler *PostReactionHandler) GetAllReactions(w http.ResponseWriter, r *ht
 	defer util.Tracer.FinishSpan(span)
 	vars := mux.Vars(r)
 	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
-	likes, dislikes, err := handler.PostReactionService.GetAllReactions(ctx, vars["postID"])
+	counts, err := handler.PostReactionService.GetReactionCounts(ctx, vars["postID"])
 	if err != nil {
 		util.Tracer.LogError(span, err)
 		fmt.Println(err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-	type ReturnDTO struct {
-		Likes    []string `json:"likes"`
-		Dislikes []string `json:"dislikes"`
-	}
-	ret := ReturnDTO{Likes: likes, Dislikes: dislikes}
-	js, err := json.Marshal(ret)
+	js, err := json.Marshal(counts)
 	if err != nil {
 		util.Tracer.LogError(span, err)
 		fmt.Println(err)
@@ -259,13 +251,70 @@ func (handler *PostReactionHandler) GetAllReactions(w http.ResponseWriter, r *ht
 	w.Header().Set("Content-Type", "application/json")
 }
 
+func (handler *PostReactionHandler) GetReactors(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("GetReactors-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	vars := mux.Vars(r)
+	reactionType := model.GetReactionType(vars["type"])
+	if reactionType == model.NONE {
+		util.Tracer.LogError(span, fmt.Errorf("bad reaction type in request"))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	page, err := getPage(r)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	reactors, err := handler.PostReactionService.GetReactors(ctx, vars["postID"], reactionType, page)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	js, err := json.Marshal(reactors)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(js)
+}
+
+// GetReactionOptions lists the reactions viewers can leave on posts.
+func (handler *PostReactionHandler) GetReactionOptions(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("GetReactionOptions-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	js, err := json.Marshal(model.GetReactionOptions())
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(js)
+}
+
 func (handler *PostReactionHandler) GetAllComments(w http.ResponseWriter, r *http.Request) {
 	span := util.Tracer.StartSpanFromRequest("GetAllComments-handler", r)
 	defer util.Tracer.FinishSpan(span)
 
 	vars := mux.Vars(r)
+	page, err := getPage(r)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
 	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
-	comments, err := handler.PostReactionService.GetAllComments(ctx, vars["postID"])
+	comments, err := handler.PostReactionService.GetComments(ctx, vars["postID"], page)
 	if err != nil {
 		util.Tracer.LogError(span, err)
 		fmt.Println(err)
@@ -283,3 +332,92 @@ func (handler *PostReactionHandler) GetAllComments(w http.ResponseWriter, r *htt
 	_, _ = w.Write(js)
 	w.Header().Set("Content-Type", "application/json")
 }
+
+func (handler *PostReactionHandler) GetEngagement(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("GetEngagement-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	type data struct {
+		Ids []string `json:"ids"`
+	}
+	var input data
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	engagement, err := handler.PostReactionService.GetEngagement(ctx, input.Ids)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	js, err := json.Marshal(engagement)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(js)
+}
+
+func (handler *PostReactionHandler) GetInteractions(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("GetInteractions-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	params := mux.Vars(r)
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	interactions, err := handler.PostReactionService.GetInteractions(ctx, util.String2Uint(params["profileID"]))
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	js, err := json.Marshal(interactions)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(js)
+}
+
+func (handler *PostReactionHandler) GetDailyEngagement(w http.ResponseWriter, r *http.Request) {
+	span := util.Tracer.StartSpanFromRequest("GetDailyEngagement-handler", r)
+	defer util.Tracer.FinishSpan(span)
+
+	params := mux.Vars(r)
+	from, err := time.Parse(time.RFC3339, r.URL.Query().Get("from"))
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	to, err := time.Parse(time.RFC3339, r.URL.Query().Get("to"))
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	ctx := util.Tracer.ContextWithSpan(context.Background(), span)
+	engagement, err := handler.PostReactionService.GetDailyEngagement(ctx, params["postID"], from, to)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	js, err := json.Marshal(engagement)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(js)
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"net/http"
 	"nistagram/postreaction/handler"
+	"nistagram/postreaction/model"
 	"nistagram/postreaction/repository"
 	"nistagram/postreaction/service"
 	"nistagram/util"
@@ -52,10 +54,41 @@ func initCollections(client *mongo.Client) {
 	const reactionsCollectionName = "reactions"
 	const reportsCollectionName = "reports"
 	const commentsCollectionName = "comments"
+	const countersCollectionName = "counters"
+	const moderationLogCollectionName = "moderationlog"
 
 	createCollection(client,reactionDbName,reactionsCollectionName)
 	createCollection(client,reactionDbName,reportsCollectionName)
 	createCollection(client,reactionDbName,commentsCollectionName)
+	createCollection(client,reactionDbName,countersCollectionName)
+	createCollection(client,reactionDbName,moderationLogCollectionName)
+	createIndexes(client, reactionDbName, reactionsCollectionName, []mongo.IndexModel{
+		{Keys: bson.D{{"postid", 1}, {"reactiontype", 1}, {"time", -1}, {"_id", -1}}},
+		{Keys: bson.D{{"profileid", 1}, {"reactiontype", 1}}},
+		{Keys: bson.D{{"profileid", 1}, {"postid", 1}}},
+	})
+	createIndexes(client, reactionDbName, commentsCollectionName, []mongo.IndexModel{
+		{Keys: bson.D{{"postid", 1}, {"parentid", 1}, {"time", 1}, {"_id", 1}}},
+		{Keys: bson.D{{"parentid", 1}, {"time", 1}, {"_id", 1}}},
+		{Keys: bson.D{{"profileid", 1}}},
+	})
+	createIndexes(client, reactionDbName, reportsCollectionName, []mongo.IndexModel{
+		{Keys: bson.D{{"targettype", 1}, {"targetid", 1}, {"status", 1}}},
+		{Keys: bson.D{{"status", 1}, {"time", 1}}},
+	})
+	createIndexes(client, reactionDbName, moderationLogCollectionName, []mongo.IndexModel{
+		{Keys: bson.D{{"time", -1}, {"_id", -1}}},
+		{Keys: bson.D{{"targettype", 1}, {"targetid", 1}, {"time", -1}}},
+		{Keys: bson.D{{"moderatorid", 1}, {"time", -1}}},
+	})
+}
+
+func createIndexes(client *mongo.Client, dbName string, collectionName string, indexes []mongo.IndexModel) {
+	if _, err := client.Database(dbName).Collection(collectionName).Indexes().CreateMany(context.TODO(), indexes); err != nil {
+		fmt.Println(err)
+	} else {
+		fmt.Println("Create " + collectionName + " indexes success")
+	}
 }
 
 func createCollection(client *mongo.Client,dbName string, collectionName string) {
@@ -87,18 +120,45 @@ func handleFunc(handler *handler.PostReactionHandler) {
 		util.RBAC(handler.DeleteReaction, "REACT_ON_POST", false)).Methods("DELETE") //frontend func
 	router.HandleFunc("/comment",
 		util.RBAC(handler.CommentPost, "REACT_ON_POST", false)).Methods("POST") //frontend func
+	router.HandleFunc("/comment/{commentID}",
+		util.RBAC(handler.EditComment, "REACT_ON_POST", false)).Methods("PUT") //frontend func
+	router.HandleFunc("/comment/{commentID}",
+		util.RBAC(handler.DeleteComment, "REACT_ON_POST", false)).Methods("DELETE") //frontend func
+	router.HandleFunc("/comment/{commentID}/replies", handler.GetReplies).Methods("GET") //frontend func
 	router.HandleFunc("/report",
-		util.RBAC(handler.ReportPost, "REPORT_POST", false)).Methods("POST") //frontend func
+		util.RBAC(handler.Report, "REPORT_POST", false)).Methods("POST") //frontend func
 	router.HandleFunc("/my-reactions/{type}",
 		util.RBAC(handler.GetMyReactions, "READ_REACTIONS", true)).Methods("GET") //frontend func
 	router.HandleFunc("/all-reactions/{postID}", handler.GetAllReactions).Methods("GET") //frontend func
+	router.HandleFunc("/reactions/{postID}/{type}", handler.GetReactors).Methods("GET") //frontend func
+	router.HandleFunc("/reaction-options", handler.GetReactionOptions).Methods("GET") //frontend func
 	router.HandleFunc("/all-comments/{postID}", handler.GetAllComments).Methods("GET") //frontend func
-	router.HandleFunc("/get-reaction-types/{profileID}",
-		util.MSAuth(handler.GetReactionTypes, []string{"post"})).Methods("POST")
-	router.HandleFunc("/report",
-		util.RBAC(handler.GetAllReports, "READ_REPORTS", true)).Methods("GET")	  //frontend func
+	router.HandleFunc("/counts",
+		util.MSAuth(handler.GetPostCounts, []string{"post"})).Methods("POST")
+	router.HandleFunc("/moderation/queue",
+		util.RBAC(handler.GetModerationQueue, "READ_REPORTS", true)).Methods("GET") //frontend func
+	router.HandleFunc("/moderation/log",
+		util.RBAC(handler.GetModerationLog, "READ_REPORTS", false)).Methods("GET") //frontend func
+	router.HandleFunc("/moderation/{targetType}/{targetID}/review",
+		util.RBAC(handler.ReviewReports, "READ_REPORTS", false)).Methods("PUT") //frontend func
+	router.HandleFunc("/moderation/{targetType}/{targetID}/remove",
+		util.RBAC(handler.RemoveTarget, "DELETE_POST", false)).Methods("POST") //frontend func
+	router.HandleFunc("/moderation/{targetType}/{targetID}/warn",
+		util.RBAC(handler.WarnOwner, "READ_REPORTS", false)).Methods("POST") //frontend func
+	router.HandleFunc("/moderation/{targetType}/{targetID}/ban",
+		util.RBAC(handler.BanOwner, "DELETE_PROFILE", false)).Methods("POST") //frontend func
+	router.HandleFunc("/moderation/{targetType}/{targetID}/dismiss",
+		util.RBAC(handler.DismissReports, "READ_REPORTS", false)).Methods("POST") //frontend func
 	router.HandleFunc("/report/{postId}",
 		util.MSAuth(handler.DeletePostsReports, []string{"post"})).Methods("DELETE")
+	router.HandleFunc("/post/{postId}",
+		util.MSAuth(handler.DeletePostData, []string{"post"})).Methods("DELETE")
+	router.HandleFunc("/engagement",
+		util.MSAuth(handler.GetEngagement, []string{"post"})).Methods("POST")
+	router.HandleFunc("/engagement/{postID}/daily",
+		util.MSAuth(handler.GetDailyEngagement, []string{"post"})).Methods("GET")
+	router.HandleFunc("/interactions/{profileID}",
+		util.MSAuth(handler.GetInteractions, []string{"post"})).Methods("GET")
 	fmt.Println("Post reaction server started...")
 	host, port := util.GetPostReactionHostAndPort()
 	var err error
@@ -122,12 +182,60 @@ func closeConnection(client *mongo.Client) {
 	fmt.Println("Connection to MongoDB closed.")
 }
 
+// initReactionTypes configures the offered reactions from REACTION_TYPES, keeping the default set when it is not set
+// or not valid.
+func initReactionTypes() {
+	value, ok := os.LookupEnv("REACTION_TYPES")
+	if !ok {
+		return
+	}
+	if err := model.ConfigureReactionTypes(value); err != nil {
+		fmt.Println(err)
+	}
+}
+
+// backfillReactions names the reactions stored as numbers before the server starts, as they cannot be read until
+// they are named. It retries until the backfill succeeds.
+func backfillReactions(postReactionService *service.PostReactionService) {
+	for {
+		err := postReactionService.BackfillReactions(context.Background())
+		if err == nil {
+			return
+		}
+		fmt.Println(err)
+		fmt.Println("Cannot backfill reactions! Sleeping 10s and then retrying....")
+		time.Sleep(10 * time.Second)
+	}
+}
+
+// runReportBackfill moves the reports filed before moderation to targets, so they show up in the moderation queue.
+func runReportBackfill(postReactionService *service.PostReactionService) {
+	if err := postReactionService.BackfillReports(context.Background()); err != nil {
+		fmt.Println(err)
+	}
+}
+
+// runCounterReconciliation rebuilds the post counters, which also counts the posts reacted on before there were
+// counters, and then does so every hour.
+func runCounterReconciliation(postReactionService *service.PostReactionService) {
+	for {
+		if err := postReactionService.ReconcileCounters(context.Background()); err != nil {
+			fmt.Println(err)
+		}
+		time.Sleep(time.Hour)
+	}
+}
+
 func main() {
 	util.TracerInit("postreaction")
+	initReactionTypes()
 	client := initDB()
 	defer closeConnection(client)
 	postReactionRepo := initPostRepo(client)
 	postReactionService := initService(postReactionRepo)
+	backfillReactions(postReactionService)
+	go runReportBackfill(postReactionService)
+	go runCounterReconciliation(postReactionService)
 	postReactionHandler := initHandler(postReactionService)
 	_ = util.SetupMSAuth("postreaction")
 	handleFunc(postReactionHandler)
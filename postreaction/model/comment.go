@@ -1,10 +1,26 @@
 package model
 
-import "time"
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
 
+// Comment is left on a post, or replies to such a comment; replies are one level deep, so ParentID is always a
+// comment on the post itself, and it is nil for comments on the post.
 type Comment struct {
-	PostID    string    `json:"postId"`
-	ProfileID uint      `json:"profileId"`
-	Content   string    `json:"content"`
-	Time      time.Time `json:"time"`
+	ID        primitive.ObjectID `bson:"_id" json:"id"`
+	PostID    string             `json:"postId"`
+	ParentID  primitive.ObjectID `json:"parentId"`
+	ProfileID uint               `json:"profileId"`
+	Content   string             `json:"content"`
+	Time      time.Time          `json:"time"`
+	EditedAt  time.Time          `json:"editedAt"`
+}
+
+func (comment *Comment) IsReply() bool {
+	return !comment.ParentID.IsZero()
+}
+
+func (comment *Comment) IsEdited() bool {
+	return !comment.EditedAt.IsZero()
 }
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"strings"
+	"time"
+)
+
+// ModerationAction is what a moderator did about a reported target. REVIEW only claims the reports of a target; the
+// other actions close them.
+type ModerationAction int
+
+const (
+	NO_ACTION ModerationAction = iota
+	REVIEW
+	REMOVE
+	WARN
+	BAN
+	DISMISS
+)
+
+func GetModerationAction(action string) ModerationAction {
+	switch strings.ToLower(action) {
+	case "review":
+		return REVIEW
+	case "remove":
+		return REMOVE
+	case "warn":
+		return WARN
+	case "ban":
+		return BAN
+	case "dismiss":
+		return DISMISS
+	}
+	return NO_ACTION
+}
+
+func (e ModerationAction) ToString() string {
+	switch e {
+	case REVIEW:
+		return "REVIEW"
+	case REMOVE:
+		return "REMOVE"
+	case WARN:
+		return "WARN"
+	case BAN:
+		return "BAN"
+	case DISMISS:
+		return "DISMISS"
+	default:
+		return "NONE"
+	}
+}
+
+// ModerationLogEntry records an action a moderator took on a target, with the reports it closed. A moderator ID of
+// 0 means the target was removed outside of moderation, like a post deleted by its publisher.
+type ModerationLogEntry struct {
+	ID            primitive.ObjectID   `bson:"_id" json:"id"`
+	ModeratorID   uint                 `json:"moderatorId"`
+	Action        ModerationAction     `json:"action"`
+	TargetType    TargetType           `json:"targetType"`
+	TargetID      string               `json:"targetId"`
+	TargetOwnerID uint                 `json:"targetOwnerId"`
+	ReportIDs     []primitive.ObjectID `json:"reportIds"`
+	Note          string               `json:"note"`
+	Time          time.Time            `json:"time"`
+}
@@ -0,0 +1,15 @@
+package model
+
+// PostCounters keeps how many reactions of each type and how many comments a post has, so that feeds do not count
+// them every time. They change with every reaction and comment and are rebuilt from those now and then, which repairs
+// the updates they missed.
+type PostCounters struct {
+	PostID    string               `bson:"_id"`
+	Reactions map[ReactionType]int `bson:"reactions"`
+	Comments  int                  `bson:"comments"`
+	Version   int                  `bson:"version"`
+}
+
+func NewPostCounters(postID string) PostCounters {
+	return PostCounters{PostID: postID, Reactions: make(map[ReactionType]int)}
+}
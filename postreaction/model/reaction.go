@@ -1,7 +1,15 @@
 package model
 
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// Time is when the profile last set the reaction.
 type Reaction struct {
-	ReactionType ReactionType `json:"reactionType"`
-	PostID       string       `json:"postId"`
-	ProfileID    uint         `json:"profileId"`
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ReactionType ReactionType       `json:"reactionType"`
+	PostID       string             `json:"postId"`
+	ProfileID    uint               `json:"profileId"`
+	Time         time.Time          `json:"time"`
 }
@@ -1,31 +1,102 @@
 package model
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
-type ReactionType int
+// ReactionType is the name a reaction is stored under. LIKE and DISLIKE are always offered, as campaign statistics
+// and the ranked home feed count them; the other reactions are configured with ConfigureReactionTypes.
+type ReactionType string
 
 const (
-	LIKE ReactionType = iota
-	DISLIKE
-	NONE
+	LIKE    ReactionType = "like"
+	DISLIKE ReactionType = "dislike"
+	NONE    ReactionType = "none"
 )
 
+// DefaultReactionTypes is the set of reactions offered when none are configured, as comma separated name=emoji pairs.
+const DefaultReactionTypes = "like=👍,dislike=👎,love=❤️,haha=😂,wow=😮,sad=😢"
+
+// ReactionOption is a reaction viewers can leave on a post, with the emoji it is shown as.
+type ReactionOption struct {
+	Type  ReactionType `json:"type"`
+	Emoji string       `json:"emoji"`
+}
+
+var reactionOptions, _ = parseReactionOptions(DefaultReactionTypes)
+
+// ConfigureReactionTypes sets the offered reactions from comma separated name=emoji pairs, like "like=👍,love=❤️".
+// Names are lower case letters and underscores; like and dislike are added in front when they are left out.
+func ConfigureReactionTypes(value string) error {
+	options, err := parseReactionOptions(value)
+	if err != nil {
+		return err
+	}
+	reactionOptions = options
+	return nil
+}
+
+// GetReactionOptions returns the offered reactions in the order they were configured.
+func GetReactionOptions() []ReactionOption {
+	return append(make([]ReactionOption, 0, len(reactionOptions)), reactionOptions...)
+}
+
 func GetReactionTypeString(reactionType ReactionType) string {
-	switch reactionType {
-	case LIKE:
-		return "like"
-	case DISLIKE:
-		return "dislike"
+	if reactionType == "" {
+		return string(NONE)
 	}
-	return "none"
+	return string(reactionType)
 }
 
+// GetReactionType returns NONE for reactions that are not offered.
 func GetReactionType(reactionType string) ReactionType {
-	if strings.ToLower(reactionType) == "like" {
-		return LIKE
-	}
-	if strings.ToLower(reactionType) == "dislike" {
-		return DISLIKE
+	name := ReactionType(strings.ToLower(strings.TrimSpace(reactionType)))
+	for _, option := range reactionOptions {
+		if option.Type == name {
+			return name
+		}
 	}
 	return NONE
 }
+
+func parseReactionOptions(value string) ([]ReactionOption, error) {
+	options := make([]ReactionOption, 0)
+	seen := make(map[ReactionType]bool)
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		name := ReactionType(strings.ToLower(strings.TrimSpace(parts[0])))
+		if len(parts) != 2 || !isReactionName(string(name)) || name == NONE || seen[name] {
+			return nil, fmt.Errorf("invalid reaction type %q", pair)
+		}
+		emoji := strings.TrimSpace(parts[1])
+		if emoji == "" {
+			return nil, fmt.Errorf("reaction type %s has no emoji", name)
+		}
+		seen[name] = true
+		options = append(options, ReactionOption{Type: name, Emoji: emoji})
+	}
+	required := make([]ReactionOption, 0)
+	if !seen[LIKE] {
+		required = append(required, ReactionOption{Type: LIKE, Emoji: "👍"})
+	}
+	if !seen[DISLIKE] {
+		required = append(required, ReactionOption{Type: DISLIKE, Emoji: "👎"})
+	}
+	return append(required, options...), nil
+}
+
+func isReactionName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && r != '_' {
+			return false
+		}
+	}
+	return true
+}
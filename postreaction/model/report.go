@@ -5,10 +5,48 @@ import (
 	"time"
 )
 
+// Report is a complaint of one profile about a target. It stays open until a moderator acts on the target or
+// dismisses its reports, and then keeps who closed it, with which action, and when. Evidence is a copy of the
+// target taken when the report was filed, so moderators see what was reported even if it was edited or deleted since.
 type Report struct {
-	ID       primitive.ObjectID  `bson:"_id" json:"id,omitempty"`
-	PostID   string              `json:"postId"`
-	Time     time.Time           `json:"time"`
-	Reason   string              `json:"reason"`
-	IsDeleted bool				 `json:"isDeleted"`
+	ID            primitive.ObjectID `bson:"_id" json:"id,omitempty"`
+	TargetType    TargetType         `json:"targetType"`
+	TargetID      string             `json:"targetId"`
+	TargetOwnerID uint               `json:"targetOwnerId"`
+	ReporterID    uint               `json:"reporterId"`
+	Category      ReportCategory     `json:"category"`
+	Reason        string             `json:"reason"`
+	Evidence      *ReportEvidence    `json:"evidence"`
+	Status        ReportStatus       `json:"status"`
+	Time          time.Time          `json:"time"`
+	ReviewerID    uint               `json:"reviewerId"`
+	Action        ModerationAction   `json:"action"`
+	ClosedAt      *time.Time         `json:"closedAt"`
+}
+
+// ReportEvidence is what the reported target looked like: the text of a comment or message, the username and
+// biography of a profile. PostID is the post a comment was left on or a message shared.
+type ReportEvidence struct {
+	Text      string    `json:"text"`
+	MediaPath string    `json:"mediaPath"`
+	PostID    string    `json:"postId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ReportGroup gathers the open reports about one target, oldest first.
+type ReportGroup struct {
+	TargetType    TargetType `bson:"targettype"`
+	TargetID      string     `bson:"targetid"`
+	TargetOwnerID uint       `bson:"targetownerid"`
+	ReviewerID    uint       `bson:"reviewerid"`
+	FirstReported time.Time  `bson:"firstreported"`
+	LastReported  time.Time  `bson:"lastreported"`
+	Reports       []Report   `bson:"reports"`
+}
+
+func (group ReportGroup) Status() ReportStatus {
+	if group.ReviewerID != 0 {
+		return UNDER_REVIEW
+	}
+	return OPEN
 }
@@ -0,0 +1,64 @@
+package model
+
+import "strings"
+
+// ReportCategory is what a reporter says is wrong with the target; reports filed before there were categories are
+// OTHER.
+type ReportCategory int
+
+const (
+	OTHER ReportCategory = iota
+	SPAM
+	HARASSMENT
+	HATE_SPEECH
+	NUDITY
+	VIOLENCE
+	MISINFORMATION
+	INTELLECTUAL_PROPERTY
+	NO_CATEGORY
+)
+
+func GetReportCategory(category string) ReportCategory {
+	switch strings.ToLower(category) {
+	case "other":
+		return OTHER
+	case "spam":
+		return SPAM
+	case "harassment":
+		return HARASSMENT
+	case "hate_speech":
+		return HATE_SPEECH
+	case "nudity":
+		return NUDITY
+	case "violence":
+		return VIOLENCE
+	case "misinformation":
+		return MISINFORMATION
+	case "intellectual_property":
+		return INTELLECTUAL_PROPERTY
+	}
+	return NO_CATEGORY
+}
+
+func (e ReportCategory) ToString() string {
+	switch e {
+	case OTHER:
+		return "OTHER"
+	case SPAM:
+		return "SPAM"
+	case HARASSMENT:
+		return "HARASSMENT"
+	case HATE_SPEECH:
+		return "HATE_SPEECH"
+	case NUDITY:
+		return "NUDITY"
+	case VIOLENCE:
+		return "VIOLENCE"
+	case MISINFORMATION:
+		return "MISINFORMATION"
+	case INTELLECTUAL_PROPERTY:
+		return "INTELLECTUAL_PROPERTY"
+	default:
+		return "NONE"
+	}
+}
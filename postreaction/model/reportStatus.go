@@ -0,0 +1,44 @@
+package model
+
+import "strings"
+
+// ReportStatus is where a report is in moderation; OPEN is the zero value, so reports filed before there were
+// statuses are open.
+type ReportStatus int
+
+const (
+	OPEN ReportStatus = iota
+	UNDER_REVIEW
+	ACTIONED
+	DISMISSED
+)
+
+func GetReportStatus(status string) ReportStatus {
+	switch strings.ToLower(status) {
+	case "under_review":
+		return UNDER_REVIEW
+	case "actioned":
+		return ACTIONED
+	case "dismissed":
+		return DISMISSED
+	}
+	return OPEN
+}
+
+func (e ReportStatus) ToString() string {
+	switch e {
+	case UNDER_REVIEW:
+		return "UNDER_REVIEW"
+	case ACTIONED:
+		return "ACTIONED"
+	case DISMISSED:
+		return "DISMISSED"
+	default:
+		return "OPEN"
+	}
+}
+
+// IsClosed tells whether a moderator already acted on the report.
+func (e ReportStatus) IsClosed() bool {
+	return e == ACTIONED || e == DISMISSED
+}
@@ -0,0 +1,43 @@
+package model
+
+import "strings"
+
+// TargetType is the kind of thing a report is about.
+type TargetType int
+
+const (
+	POST_TARGET TargetType = iota
+	PROFILE_TARGET
+	COMMENT_TARGET
+	MESSAGE_TARGET
+	NO_TARGET
+)
+
+func GetTargetType(targetType string) TargetType {
+	switch strings.ToLower(targetType) {
+	case "post":
+		return POST_TARGET
+	case "profile":
+		return PROFILE_TARGET
+	case "comment":
+		return COMMENT_TARGET
+	case "message":
+		return MESSAGE_TARGET
+	}
+	return NO_TARGET
+}
+
+func (e TargetType) ToString() string {
+	switch e {
+	case POST_TARGET:
+		return "POST"
+	case PROFILE_TARGET:
+		return "PROFILE"
+	case COMMENT_TARGET:
+		return "COMMENT"
+	case MESSAGE_TARGET:
+		return "MESSAGE"
+	default:
+		return "NONE"
+	}
+}
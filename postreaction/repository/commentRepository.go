@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	postModel "nistagram/post/model"
+	"nistagram/postreaction/model"
+	"nistagram/util"
+	"time"
+)
+
+const parentIDColumn = "parentid"
+
+func (repo *PostReactionRepository) CommentPost(ctx context.Context, comment *model.Comment) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "CommentPost-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	commentsCollection := repo.getCollection(commentsCollectionName)
+	_, err := commentsCollection.InsertOne(emptyContext, comment)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	repo.incrementCounters(util.Tracer.ContextWithSpan(ctx, span), comment.PostID, bson.D{{commentsCounter, 1}})
+	return nil
+}
+
+func (repo *PostReactionRepository) GetComment(ctx context.Context, id primitive.ObjectID) (model.Comment, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetComment-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	var comment model.Comment
+	err := repo.getCollection(commentsCollectionName).FindOne(emptyContext, bson.D{{"_id", id}}).Decode(&comment)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+	}
+	return comment, err
+}
+
+// GetComments pages through the comments on the post, or through the replies to a comment when parentID is set.
+// A thread reads in the order it was written, so comments are ordered by time and then by ID, both ascending.
+func (repo *PostReactionRepository) GetComments(ctx context.Context, postID string, parentID primitive.ObjectID, page postModel.Page) ([]model.Comment, string, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetComments-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	conditions := bson.A{bson.D{{postIDColumn, postID}}}
+	if parentID.IsZero() {
+		// comments left before replies existed have no parent ID at all
+		conditions = append(conditions, bson.D{{parentIDColumn, bson.D{{"$in", bson.A{primitive.NilObjectID, nil}}}}})
+	} else {
+		conditions = append(conditions, bson.D{{parentIDColumn, parentID}})
+	}
+	if page.Cursor != nil {
+		conditions = append(conditions, bson.D{{"$or", bson.A{
+			bson.D{{timeColumn, bson.D{{"$gt", page.Cursor.PublishDate}}}},
+			bson.D{{timeColumn, page.Cursor.PublishDate}, {"_id", bson.D{{"$gt", page.Cursor.ID}}}},
+		}}})
+	}
+	opts := options.Find().SetSort(bson.D{{timeColumn, 1}, {"_id", 1}}).SetLimit(int64(page.Limit + 1))
+	cursor, err := repo.getCollection(commentsCollectionName).Find(emptyContext, bson.D{{"$and", conditions}}, opts)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, "", err
+	}
+	comments := make([]model.Comment, 0)
+	if err = cursor.All(emptyContext, &comments); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, "", err
+	}
+	if len(comments) <= page.Limit {
+		return comments, "", nil
+	}
+	comments = comments[:page.Limit]
+	last := comments[len(comments)-1]
+	return comments, postModel.Cursor{PublishDate: last.Time, ID: last.ID}.Encode(), nil
+}
+
+// CountReplies returns the number of replies to each of the comments; comments without replies are left out.
+func (repo *PostReactionRepository) CountReplies(ctx context.Context, commentIDs []primitive.ObjectID) (map[primitive.ObjectID]int, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "CountReplies-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{parentIDColumn, bson.D{{"$in", commentIDs}}}}}},
+		{{"$group", bson.D{{"_id", "$" + parentIDColumn}, {"count", bson.D{{"$sum", 1}}}}}},
+	}
+	cursor, err := repo.getCollection(commentsCollectionName).Aggregate(emptyContext, pipeline)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	var counts []struct {
+		ParentID primitive.ObjectID `bson:"_id"`
+		Count    int                `bson:"count"`
+	}
+	if err = cursor.All(emptyContext, &counts); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	ret := make(map[primitive.ObjectID]int)
+	for _, count := range counts {
+		ret[count.ParentID] = count.Count
+	}
+	return ret, nil
+}
+
+func (repo *PostReactionRepository) UpdateComment(ctx context.Context, id primitive.ObjectID, content string, editedAt time.Time) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "UpdateComment-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	update := bson.D{{"$set", bson.D{{"content", content}, {"editedat", editedAt}}}}
+	result, err := repo.getCollection(commentsCollectionName).UpdateOne(emptyContext, bson.D{{"_id", id}}, update)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// DeleteComment removes the comment together with its replies and returns the IDs of the comments it removed.
+func (repo *PostReactionRepository) DeleteComment(ctx context.Context, comment model.Comment) ([]primitive.ObjectID, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "DeleteComment-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	collection := repo.getCollection(commentsCollectionName)
+	filter := bson.D{{"$or", bson.A{bson.D{{"_id", comment.ID}}, bson.D{{parentIDColumn, comment.ID}}}}}
+	values, err := collection.Distinct(emptyContext, "_id", filter)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, 0)
+	for _, value := range values {
+		if id, ok := value.(primitive.ObjectID); ok {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+	result, err := collection.DeleteMany(emptyContext, bson.D{{"_id", bson.D{{"$in", ids}}}})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	if result.DeletedCount == 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+	repo.incrementCounters(util.Tracer.ContextWithSpan(ctx, span), comment.PostID,
+		bson.D{{commentsCounter, -result.DeletedCount}})
+	return ids, nil
+}
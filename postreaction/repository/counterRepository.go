@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"nistagram/postreaction/model"
+	"nistagram/util"
+)
+
+const countersCollectionName = "counters"
+const reactionsCounter = "reactions"
+const commentsCounter = "comments"
+const versionCounter = "version"
+const rebuildBatchSize = 100
+const duplicateKeyCode = 11000
+
+// GetCounters returns the counters of the posts; posts nobody reacted on or commented are left out.
+func (repo *PostReactionRepository) GetCounters(ctx context.Context, postIDs []string) (map[string]model.PostCounters, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetCounters-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	filter := bson.D{{"_id", bson.D{{"$in", postIDs}}}}
+	cursor, err := repo.getCollection(countersCollectionName).Find(emptyContext, filter)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	var counters []model.PostCounters
+	if err = cursor.All(emptyContext, &counters); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	ret := make(map[string]model.PostCounters)
+	for _, value := range counters {
+		ret[value.PostID] = value
+	}
+	return ret, nil
+}
+
+// GetProfileReactionTypes returns the reactions the profile left on the posts; posts it did not react on are left out.
+func (repo *PostReactionRepository) GetProfileReactionTypes(ctx context.Context, profileID uint, postIDs []string) (map[string]model.ReactionType, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetProfileReactionTypes-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	filter := bson.D{{profileIDColumn, profileID}, {postIDColumn, bson.D{{"$in", postIDs}}}}
+	cursor, err := repo.getCollection(reactionsCollectionName).Find(emptyContext, filter)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	var reactions []model.Reaction
+	if err = cursor.All(emptyContext, &reactions); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	ret := make(map[string]model.ReactionType)
+	for _, reaction := range reactions {
+		ret[reaction.PostID] = reaction.ReactionType
+	}
+	return ret, nil
+}
+
+// RebuildCounters counts the reactions and comments of every post again and sets the counters to the result, a
+// batch of posts at a time. Counters incremented while their post is counted are left as they are, until the next
+// time it runs.
+func (repo *PostReactionRepository) RebuildCounters(ctx context.Context) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "RebuildCounters-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	postIDs, err := repo.getCountedPostIDs(nextCtx)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	for start := 0; start < len(postIDs); start += rebuildBatchSize {
+		end := start + rebuildBatchSize
+		if end > len(postIDs) {
+			end = len(postIDs)
+		}
+		if err = repo.rebuildCountersBatch(nextCtx, postIDs[start:end]); err != nil {
+			util.Tracer.LogError(span, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// getCountedPostIDs returns the posts that have counters, reactions or comments.
+func (repo *PostReactionRepository) getCountedPostIDs(ctx context.Context) ([]string, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "getCountedPostIDs-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	ret := make([]string, 0)
+	seen := make(map[string]bool)
+	for collectionName, field := range map[string]string{countersCollectionName: "_id",
+		reactionsCollectionName: postIDColumn, commentsCollectionName: postIDColumn} {
+		postIDs, err := repo.getCollection(collectionName).Distinct(emptyContext, field, bson.D{})
+		if err != nil {
+			util.Tracer.LogError(span, err)
+			return nil, err
+		}
+		for _, postID := range postIDs {
+			if id, ok := postID.(string); ok && !seen[id] {
+				seen[id] = true
+				ret = append(ret, id)
+			}
+		}
+	}
+	return ret, nil
+}
+
+// rebuildCountersBatch counts the reactions and comments of the posts and sets their counters, but only where the
+// version read before counting is still stored; incrementCounters bumps the version, so the increments made while
+// the posts were counted are not overwritten.
+func (repo *PostReactionRepository) rebuildCountersBatch(ctx context.Context, postIDs []string) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "rebuildCountersBatch-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	versions := make(map[string]int)
+	stored, err := repo.GetCounters(util.Tracer.ContextWithSpan(ctx, span), postIDs)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	for postID, value := range stored {
+		versions[postID] = value.Version
+	}
+
+	counters := make(map[string]model.PostCounters)
+	for _, postID := range postIDs {
+		counters[postID] = model.NewPostCounters(postID)
+	}
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{postIDColumn, bson.D{{"$in", postIDs}}}}}},
+		{{"$group", bson.D{
+			{"_id", bson.D{{"post", "$" + postIDColumn}, {"type", "$" + reactionTypeColumn}}},
+			{"count", bson.D{{"$sum", 1}}},
+		}}},
+	}
+	cursor, err := repo.getCollection(reactionsCollectionName).Aggregate(emptyContext, pipeline)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	var reactionCounts []struct {
+		ID struct {
+			PostID string             `bson:"post"`
+			Type   model.ReactionType `bson:"type"`
+		} `bson:"_id"`
+		Count int `bson:"count"`
+	}
+	if err = cursor.All(emptyContext, &reactionCounts); err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	for _, count := range reactionCounts {
+		counters[count.ID.PostID].Reactions[count.ID.Type] = count.Count
+	}
+	commentCounts, err := repo.countByPost(util.Tracer.ContextWithSpan(ctx, span), commentsCollectionName,
+		bson.D{{postIDColumn, bson.D{{"$in", postIDs}}}}, 0)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	for postID, count := range commentCounts {
+		value := counters[postID]
+		value.Comments = count
+		counters[postID] = value
+	}
+
+	models := make([]mongo.WriteModel, 0)
+	for postID, value := range counters {
+		// Counters that were never versioned have no version field; the upsert of a post whose counters were
+		// created in the meantime fails on the duplicate ID and is skipped like the other changed counters.
+		version := bson.D{{"$in", bson.A{versions[postID], nil}}}
+		if versions[postID] != 0 {
+			version = bson.D{{"$eq", versions[postID]}}
+		}
+		update := bson.D{{"$set", bson.D{{reactionsCounter, value.Reactions}, {commentsCounter, value.Comments}}}}
+		models = append(models, mongo.NewUpdateOneModel().SetFilter(bson.D{{"_id", postID}, {versionCounter, version}}).
+			SetUpdate(update).SetUpsert(true))
+	}
+	_, err = repo.getCollection(countersCollectionName).BulkWrite(emptyContext, models, options.BulkWrite().SetOrdered(false))
+	if err != nil && !onlyDuplicateKeyErrors(err) {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	return nil
+}
+
+func onlyDuplicateKeyErrors(err error) bool {
+	bulkErr, ok := err.(mongo.BulkWriteException)
+	if !ok || bulkErr.WriteConcernError != nil {
+		return false
+	}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if writeErr.Code != duplicateKeyCode {
+			return false
+		}
+	}
+	return true
+}
+
+// incrementCounters adds the increments to the counters of the post and bumps their version. The reactions and comments are already stored
+// when their counters are updated, so a failed update is only logged; RebuildCounters repairs it.
+func (repo *PostReactionRepository) incrementCounters(ctx context.Context, postID string, increments bson.D) {
+	span := util.Tracer.StartSpanFromContext(ctx, "incrementCounters-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	update := bson.D{{"$inc", append(increments, bson.E{Key: versionCounter, Value: 1})}}
+	_, err := repo.getCollection(countersCollectionName).UpdateOne(emptyContext, bson.D{{"_id", postID}}, update,
+		options.Update().SetUpsert(true))
+	if err != nil {
+		util.Tracer.LogError(span, err)
+	}
+}
+
+func reactionCounter(reactionType model.ReactionType) string {
+	return reactionsCounter + "." + string(reactionType)
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"nistagram/postreaction/model"
+	"nistagram/util"
+)
+
+// CountProfileLikes returns how many times the profile liked each post, for at most limit posts it engaged with last.
+func (repo *PostReactionRepository) CountProfileLikes(ctx context.Context, profileID uint, limit int) (map[string]int, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "CountProfileLikes-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	filter := bson.D{{profileIDColumn, profileID}, {reactionTypeColumn, model.LIKE}}
+	return repo.countByPost(nextCtx, reactionsCollectionName, filter, limit)
+}
+
+// CountProfileComments returns how many comments the profile left on each post, for at most limit posts it engaged with last.
+func (repo *PostReactionRepository) CountProfileComments(ctx context.Context, profileID uint, limit int) (map[string]int, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "CountProfileComments-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	filter := bson.D{{profileIDColumn, profileID}}
+	return repo.countByPost(nextCtx, commentsCollectionName, filter, limit)
+}
+
+// countByPost groups the matching documents by post, keeping the posts with the latest documents when limit is set.
+func (repo *PostReactionRepository) countByPost(ctx context.Context, collectionName string, filter bson.D, limit int) (map[string]int, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "countByPost-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	pipeline := mongo.Pipeline{
+		{{"$match", filter}},
+		{{"$group", bson.D{{"_id", "$" + postIDColumn}, {"count", bson.D{{"$sum", 1}}},
+			{"last", bson.D{{"$max", "$" + timeColumn}}}}}},
+	}
+	if limit > 0 {
+		pipeline = append(pipeline,
+			bson.D{{"$sort", bson.D{{"last", -1}, {"_id", 1}}}},
+			bson.D{{"$limit", limit}})
+	}
+
+	cursor, err := repo.getCollection(collectionName).Aggregate(emptyContext, pipeline)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	var counts []struct {
+		PostID string `bson:"_id"`
+		Count  int    `bson:"count"`
+	}
+	if err = cursor.All(emptyContext, &counts); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	ret := make(map[string]int)
+	for _, count := range counts {
+		ret[count.PostID] = count.Count
+	}
+	return ret, nil
+}
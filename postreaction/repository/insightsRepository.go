@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"nistagram/postreaction/model"
+	"nistagram/util"
+	"time"
+)
+
+// InsightsDayFormat names the UTC day reactions and comments are grouped by.
+const InsightsDayFormat = "%Y-%m-%d"
+
+// CountReactionsByDay counts the likes and dislikes set on the post in [from, to) by day.
+func (repo *PostReactionRepository) CountReactionsByDay(ctx context.Context, postID string, from time.Time, to time.Time) (map[string]map[model.ReactionType]int, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "CountReactionsByDay-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{postIDColumn, postID}, {timeColumn, bson.D{{"$gte", from}, {"$lt", to}}}}}},
+		{{"$group", bson.D{
+			{"_id", bson.D{{"day", dayOf("$" + timeColumn)}, {"type", "$" + reactionTypeColumn}}},
+			{"count", bson.D{{"$sum", 1}}},
+		}}},
+	}
+	cursor, err := repo.getCollection(reactionsCollectionName).Aggregate(emptyContext, pipeline)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	var counts []struct {
+		ID struct {
+			Day  string             `bson:"day"`
+			Type model.ReactionType `bson:"type"`
+		} `bson:"_id"`
+		Count int `bson:"count"`
+	}
+	if err = cursor.All(emptyContext, &counts); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	ret := make(map[string]map[model.ReactionType]int)
+	for _, count := range counts {
+		if ret[count.ID.Day] == nil {
+			ret[count.ID.Day] = make(map[model.ReactionType]int)
+		}
+		ret[count.ID.Day][count.ID.Type] = count.Count
+	}
+	return ret, nil
+}
+
+// CountCommentsByDay counts the comments left on the post in [from, to) by day.
+func (repo *PostReactionRepository) CountCommentsByDay(ctx context.Context, postID string, from time.Time, to time.Time) (map[string]int, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "CountCommentsByDay-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{postIDColumn, postID}, {timeColumn, bson.D{{"$gte", from}, {"$lt", to}}}}}},
+		{{"$group", bson.D{{"_id", dayOf("$" + timeColumn)}, {"count", bson.D{{"$sum", 1}}}}}},
+	}
+	cursor, err := repo.getCollection(commentsCollectionName).Aggregate(emptyContext, pipeline)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	var counts []struct {
+		Day   string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	if err = cursor.All(emptyContext, &counts); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	ret := make(map[string]int)
+	for _, count := range counts {
+		ret[count.Day] = count.Count
+	}
+	return ret, nil
+}
+
+func dayOf(field string) bson.D {
+	return bson.D{{"$dateToString", bson.D{{"format", InsightsDayFormat}, {"date", field}}}}
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	postModel "nistagram/post/model"
+	"nistagram/postreaction/model"
+	"nistagram/util"
+	"time"
+)
+
+const moderationLogCollectionName = "moderationlog"
+
+const targetTypeColumn = "targettype"
+const targetIDColumn = "targetid"
+const reporterIDColumn = "reporterid"
+const statusColumn = "status"
+const reviewerIDColumn = "reviewerid"
+
+// MaxModerationQueue bounds how many reported targets the moderation queue shows, the ones reported first.
+const MaxModerationQueue = 200
+
+func (repo *PostReactionRepository) CreateReport(ctx context.Context, report *model.Report) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "CreateReport-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	_, err := repo.getCollection(reportsCollectionName).InsertOne(emptyContext, report)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+	}
+	return err
+}
+
+// HasOpenReport tells whether the profile already reported the target and the report was not closed yet.
+func (repo *PostReactionRepository) HasOpenReport(ctx context.Context, reporterID uint, targetType model.TargetType, targetID string) (bool, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "HasOpenReport-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	filter := append(openReportsFilter(targetType, targetID), bson.E{Key: reporterIDColumn, Value: reporterID})
+	count, err := repo.getCollection(reportsCollectionName).CountDocuments(emptyContext, filter)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetReportGroups returns the open reports grouped by target, the targets reported first coming first.
+func (repo *PostReactionRepository) GetReportGroups(ctx context.Context, limit int) ([]model.ReportGroup, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetReportGroups-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{statusColumn, bson.D{{"$in", bson.A{model.OPEN, model.UNDER_REVIEW}}}}}}},
+		{{"$sort", bson.D{{timeColumn, 1}, {"_id", 1}}}},
+		{{"$group", bson.D{
+			{"_id", bson.D{{"type", "$" + targetTypeColumn}, {"id", "$" + targetIDColumn}}},
+			{"targettype", bson.D{{"$first", "$" + targetTypeColumn}}},
+			{"targetid", bson.D{{"$first", "$" + targetIDColumn}}},
+			{"targetownerid", bson.D{{"$max", "$targetownerid"}}},
+			{"reviewerid", bson.D{{"$max", "$" + reviewerIDColumn}}},
+			{"firstreported", bson.D{{"$min", "$" + timeColumn}}},
+			{"lastreported", bson.D{{"$max", "$" + timeColumn}}},
+			{"reports", bson.D{{"$push", "$$ROOT"}}},
+		}}},
+		{{"$sort", bson.D{{"firstreported", 1}, {"targetid", 1}}}},
+		{{"$limit", limit}},
+	}
+	cursor, err := repo.getCollection(reportsCollectionName).Aggregate(emptyContext, pipeline)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	groups := make([]model.ReportGroup, 0)
+	if err = cursor.All(emptyContext, &groups); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	return groups, nil
+}
+
+// GetOpenReports returns the reports about the target that were not closed yet.
+func (repo *PostReactionRepository) GetOpenReports(ctx context.Context, targetType model.TargetType, targetID string) ([]model.Report, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetOpenReports-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	opts := options.Find().SetSort(bson.D{{timeColumn, 1}, {"_id", 1}})
+	cursor, err := repo.getCollection(reportsCollectionName).Find(emptyContext, openReportsFilter(targetType, targetID), opts)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	reports := make([]model.Report, 0)
+	if err = cursor.All(emptyContext, &reports); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	return reports, nil
+}
+
+// ReviewReports puts the open reports about the target under review by the moderator.
+func (repo *PostReactionRepository) ReviewReports(ctx context.Context, targetType model.TargetType, targetID string, reviewerID uint) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "ReviewReports-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	update := bson.D{{"$set", bson.D{{statusColumn, model.UNDER_REVIEW}, {reviewerIDColumn, reviewerID}}}}
+	result, err := repo.getCollection(reportsCollectionName).UpdateMany(emptyContext, openReportsFilter(targetType, targetID), update)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// CloseReports closes the reports that are still open, recording the action taken on their target and by whom.
+func (repo *PostReactionRepository) CloseReports(ctx context.Context, ids []primitive.ObjectID, status model.ReportStatus, reviewerID uint, action model.ModerationAction, closedAt time.Time) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "CloseReports-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	filter := bson.D{{"_id", bson.D{{"$in", ids}}}, {statusColumn, bson.D{{"$in", bson.A{model.OPEN, model.UNDER_REVIEW}}}}}
+	update := bson.D{{"$set", bson.D{
+		{statusColumn, status},
+		{reviewerIDColumn, reviewerID},
+		{"action", action},
+		{"closedat", closedAt},
+	}}}
+	_, err := repo.getCollection(reportsCollectionName).UpdateMany(emptyContext, filter, update)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+	}
+	return err
+}
+
+func (repo *PostReactionRepository) CreateLogEntry(ctx context.Context, entry *model.ModerationLogEntry) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "CreateLogEntry-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	_, err := repo.getCollection(moderationLogCollectionName).InsertOne(emptyContext, entry)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+	}
+	return err
+}
+
+// GetLogEntries pages through the moderation log, the latest entries first. A target type other than NO_TARGET
+// narrows it to one target, and a moderator ID other than 0 to the actions of one moderator.
+func (repo *PostReactionRepository) GetLogEntries(ctx context.Context, targetType model.TargetType, targetID string, moderatorID uint, page postModel.Page) ([]model.ModerationLogEntry, string, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetLogEntries-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	filter := bson.D{}
+	if targetType != model.NO_TARGET {
+		filter = append(filter, bson.E{Key: targetTypeColumn, Value: targetType}, bson.E{Key: targetIDColumn, Value: targetID})
+	}
+	if moderatorID != 0 {
+		filter = append(filter, bson.E{Key: "moderatorid", Value: moderatorID})
+	}
+	if page.Cursor != nil {
+		filter = append(filter, bson.E{Key: "$or", Value: bson.A{
+			bson.D{{timeColumn, bson.D{{"$lt", page.Cursor.PublishDate}}}},
+			bson.D{{timeColumn, page.Cursor.PublishDate}, {"_id", bson.D{{"$lt", page.Cursor.ID}}}},
+		}})
+	}
+	opts := options.Find().SetSort(bson.D{{timeColumn, -1}, {"_id", -1}}).SetLimit(int64(page.Limit + 1))
+	cursor, err := repo.getCollection(moderationLogCollectionName).Find(emptyContext, filter, opts)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, "", err
+	}
+	entries := make([]model.ModerationLogEntry, 0)
+	if err = cursor.All(emptyContext, &entries); err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, "", err
+	}
+	if len(entries) <= page.Limit {
+		return entries, "", nil
+	}
+	entries = entries[:page.Limit]
+	last := entries[len(entries)-1]
+	return entries, postModel.Cursor{PublishDate: last.Time, ID: last.ID}.Encode(), nil
+}
+
+// BackfillReports moves the reports filed before moderation to targets. Reports of posts that were already deleted
+// are closed as actioned by the removal of the post.
+func (repo *PostReactionRepository) BackfillReports(ctx context.Context) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "BackfillReports-repository")
+	defer util.Tracer.FinishSpan(span)
+
+	filter := bson.D{{targetIDColumn, bson.D{{"$exists", false}}}}
+	update := mongo.Pipeline{
+		{{"$set", bson.D{
+			{targetTypeColumn, model.POST_TARGET},
+			{targetIDColumn, "$" + postIDColumn},
+			{"category", model.OTHER},
+			{statusColumn, bson.D{{"$cond", bson.A{"$isdeleted", model.ACTIONED, model.OPEN}}}},
+			{"action", bson.D{{"$cond", bson.A{"$isdeleted", model.REMOVE, model.NO_ACTION}}}},
+		}}},
+		{{"$unset", bson.A{postIDColumn, "isdeleted"}}},
+	}
+	_, err := repo.getCollection(reportsCollectionName).UpdateMany(emptyContext, filter, update)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+	}
+	return err
+}
+
+func openReportsFilter(targetType model.TargetType, targetID string) bson.D {
+	return bson.D{
+		{targetTypeColumn, targetType},
+		{targetIDColumn, targetID},
+		{statusColumn, bson.D{{"$in", bson.A{model.OPEN, model.UNDER_REVIEW}}}},
+	}
+}
@@ -4,8 +4,9 @@ import (
 	"context"
 	"fmt"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	postModel "nistagram/post/model"
 	"nistagram/postreaction/model"
 	"nistagram/util"
 )
@@ -18,6 +19,7 @@ const commentsCollectionName = "comments"
 const profileIDColumn = "profileid"
 const postIDColumn = "postid"
 const reactionTypeColumn = "reactiontype"
+const timeColumn = "time"
 
 var emptyContext = context.TODO()
 
@@ -29,6 +31,8 @@ func (repo *PostReactionRepository) ReactOnPost(ctx context.Context, reaction *m
 	span := util.Tracer.StartSpanFromContext(ctx, "ReactOnPost-repository")
 	defer util.Tracer.FinishSpan(span)
 
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
 	reactionsCollection := repo.getCollection(reactionsCollectionName)
 	filter := bson.D{{profileIDColumn, reaction.ProfileID}, {postIDColumn, reaction.PostID}}
 	var existingReaction model.Reaction
@@ -39,18 +43,33 @@ func (repo *PostReactionRepository) ReactOnPost(ctx context.Context, reaction *m
 			util.Tracer.LogError(span, err)
 			return nil, err
 		}
+		repo.incrementCounters(nextCtx, reaction.PostID, bson.D{{reactionCounter(reaction.ReactionType), 1}})
 		return nil, nil
 	}
+	if existingReaction.ReactionType == reaction.ReactionType {
+		return &existingReaction.ReactionType, nil
+	}
 	update := bson.D{
 		{"$set", bson.D{
 			{reactionTypeColumn, reaction.ReactionType},
+			{timeColumn, reaction.Time},
 		}},
 	}
-	result, _ := reactionsCollection.UpdateOne(emptyContext, filter, update)
+	// the reaction is only changed if nobody changed it since it was read, so the counters move from the right type
+	filter = append(filter, bson.E{Key: reactionTypeColumn, Value: existingReaction.ReactionType})
+	result, err := reactionsCollection.UpdateOne(emptyContext, filter, update)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
 	if result.MatchedCount == 0 {
 		util.Tracer.LogError(span, fmt.Errorf("reaction not updated"))
 		return nil, mongo.ErrNoDocuments
 	}
+	repo.incrementCounters(nextCtx, reaction.PostID, bson.D{
+		{reactionCounter(existingReaction.ReactionType), -1},
+		{reactionCounter(reaction.ReactionType), 1},
+	})
 	return &existingReaction.ReactionType, nil
 }
 
@@ -66,35 +85,16 @@ func (repo *PostReactionRepository) DeleteReaction(ctx context.Context, postID s
 		util.Tracer.LogError(span, err)
 		return nil, err
 	}
-	_, err = reactionsCollection.DeleteOne(emptyContext, existingReaction)
+	result, err := reactionsCollection.DeleteOne(emptyContext, filter)
 	if err != nil {
 		util.Tracer.LogError(span, err)
 		return nil, err
 	}
-	return &existingReaction, err
-}
-
-func (repo *PostReactionRepository) CommentPost(ctx context.Context, comment *model.Comment) error {
-	span := util.Tracer.StartSpanFromContext(ctx, "CommentPost-repository")
-	defer util.Tracer.FinishSpan(span)
-
-	commentsCollection := repo.getCollection(commentsCollectionName)
-	_, err := commentsCollection.InsertOne(emptyContext, comment)
-	if err != nil {
-		util.Tracer.LogError(span, err)
-	}
-	return err
-}
-
-func (repo *PostReactionRepository) ReportPost(ctx context.Context, report *model.Report) error {
-	span := util.Tracer.StartSpanFromContext(ctx, "ReportPost-repository")
-	defer util.Tracer.FinishSpan(span)
-	reportsCollection := repo.getCollection(reportsCollectionName)
-	_, err := reportsCollection.InsertOne(emptyContext, report)
-	if err != nil {
-		util.Tracer.LogError(span, err)
+	if result.DeletedCount > 0 {
+		repo.incrementCounters(util.Tracer.ContextWithSpan(ctx, span), postID,
+			bson.D{{reactionCounter(existingReaction.ReactionType), -1}})
 	}
-	return err
+	return &existingReaction, err
 }
 
 func (repo *PostReactionRepository) GetProfileReactions(ctx context.Context, reactionType model.ReactionType, profileID uint) ([]model.Reaction, error) {
@@ -122,141 +122,91 @@ func (repo *PostReactionRepository) GetProfileReactions(ctx context.Context, rea
 	return reactions, nil
 }
 
-func (repo *PostReactionRepository) GetReactionType(ctx context.Context, profileID uint, postID string) string {
-	span := util.Tracer.StartSpanFromContext(ctx, "GetReactionType-repository")
+// GetReactions pages through the reactions of one type left on the post, the latest first.
+func (repo *PostReactionRepository) GetReactions(ctx context.Context, postID string, reactionType model.ReactionType, page postModel.Page) ([]model.Reaction, string, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetReactions-repository")
 	defer util.Tracer.FinishSpan(span)
 
-	reactionsCollection := repo.getCollection(reactionsCollectionName)
-	filter := bson.D{{profileIDColumn, profileID}, {postIDColumn, postID}}
-	var existingReaction model.Reaction
-	err := reactionsCollection.FindOne(emptyContext, filter).Decode(&existingReaction)
+	filter := bson.D{{postIDColumn, postID}, {reactionTypeColumn, reactionType}}
+	if page.Cursor != nil {
+		filter = append(filter, bson.E{Key: "$or", Value: bson.A{
+			bson.D{{timeColumn, bson.D{{"$lt", page.Cursor.PublishDate}}}},
+			bson.D{{timeColumn, page.Cursor.PublishDate}, {"_id", bson.D{{"$lt", page.Cursor.ID}}}},
+		}})
+	}
+	opts := options.Find().SetSort(bson.D{{timeColumn, -1}, {"_id", -1}}).SetLimit(int64(page.Limit + 1))
+	cursor, err := repo.getCollection(reactionsCollectionName).Find(emptyContext, filter, opts)
 	if err != nil {
 		util.Tracer.LogError(span, err)
-		return "none"
+		return nil, "", err
 	}
-	return model.GetReactionTypeString(existingReaction.ReactionType)
-}
-
-func (repo *PostReactionRepository) GetAllReports(ctx context.Context) ([]model.Report, error) {
-	span := util.Tracer.StartSpanFromContext(ctx, "GetAllReports-repository")
-	defer util.Tracer.FinishSpan(span)
-
-	var reports []model.Report
-	reportsCollection := repo.getCollection(reportsCollectionName)
-	filter := bson.D{{"isdeleted", false}}
-	cursor, err := reportsCollection.Find(context.TODO(), filter)
-	if err != nil {
+	reactions := make([]model.Reaction, 0)
+	if err = cursor.All(emptyContext, &reactions); err != nil {
 		util.Tracer.LogError(span, err)
-		return nil, err
+		return nil, "", err
 	}
-	for cursor.Next(context.TODO()) {
-		var result model.Report
-		err = cursor.Decode(&result)
-		if err != nil {
-			util.Tracer.LogError(span, err)
-			return nil, err
-		}
-		reports = append(reports, result)
+	if len(reactions) <= page.Limit {
+		return reactions, "", nil
 	}
-	return reports, nil
+	reactions = reactions[:page.Limit]
+	last := reactions[len(reactions)-1]
+	return reactions, postModel.Cursor{PublishDate: last.Time, ID: last.ID}.Encode(), nil
 }
 
-func (repo *PostReactionRepository) GetReportsByPostId(ctx context.Context, postId string) ([]model.Report, error) {
-	span := util.Tracer.StartSpanFromContext(ctx, "GetReportsByPostId-repository")
+// BackfillReactions names the likes and dislikes stored by number before reactions were configurable, and gives the
+// reactions set before their time was kept the time they were created at.
+func (repo *PostReactionRepository) BackfillReactions(ctx context.Context) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "BackfillReactions-repository")
 	defer util.Tracer.FinishSpan(span)
 
-	var reports []model.Report
-	reportsCollection := repo.getCollection(reportsCollectionName)
-	filter := bson.D{{"postid", postId}}
-	cursor, err := reportsCollection.Find(context.TODO(), filter)
-	if err != nil {
+	reactionsCollection := repo.getCollection(reactionsCollectionName)
+	filter := bson.D{{reactionTypeColumn, bson.D{{"$type", "number"}}}}
+	update := mongo.Pipeline{
+		{{"$set", bson.D{{reactionTypeColumn, bson.D{{"$switch", bson.D{
+			{"branches", bson.A{
+				bson.D{{"case", bson.D{{"$eq", bson.A{"$" + reactionTypeColumn, 0}}}}, {"then", model.LIKE}},
+				bson.D{{"case", bson.D{{"$eq", bson.A{"$" + reactionTypeColumn, 1}}}}, {"then", model.DISLIKE}},
+			}},
+			{"default", model.NONE},
+		}}}}}}},
+	}
+	if _, err := reactionsCollection.UpdateMany(emptyContext, filter, update); err != nil {
 		util.Tracer.LogError(span, err)
-		return nil, err
-	}
-	for cursor.Next(context.TODO()) {
-		var result model.Report
-		err = cursor.Decode(&result)
-		if err != nil {
-			util.Tracer.LogError(span, err)
-			return nil, err
-		}
-		reports = append(reports, result)
+		return err
 	}
-	return reports, nil
-}
-
-func (repo *PostReactionRepository) DeleteReport(ctx context.Context, reportId primitive.ObjectID) error {
-	span := util.Tracer.StartSpanFromContext(ctx, "DeleteReport-repository")
-	defer util.Tracer.FinishSpan(span)
-
-	reportsCollection := repo.getCollection(reportsCollectionName)
-	filter := bson.D{{"_id", reportId}}
-	update := bson.D{
-		{"$set", bson.D{
-			{"isdeleted", true},
-		}},
+	filter = bson.D{{timeColumn, bson.D{{"$exists", false}}}}
+	update = mongo.Pipeline{
+		{{"$set", bson.D{{timeColumn, bson.D{{"$toDate", "$_id"}}}}}},
 	}
-
-	_, err := reportsCollection.UpdateOne(emptyContext, filter, update)
-	if err != nil {
+	if _, err := reactionsCollection.UpdateMany(emptyContext, filter, update); err != nil {
 		util.Tracer.LogError(span, err)
 		return err
 	}
-
 	return nil
 }
 
-func (repo *PostReactionRepository) GetAllReactions(ctx context.Context, postID string) ([]uint, []uint, error) {
-	span := util.Tracer.StartSpanFromContext(ctx, "GetAllReactions-repository")
+// DeletePostData removes every reaction, comment and report left on a post that no longer exists, and its counters.
+func (repo *PostReactionRepository) DeletePostData(ctx context.Context, postID string) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "DeletePostData-repository")
 	defer util.Tracer.FinishSpan(span)
 
-	reactionsCollection := repo.getCollection(reactionsCollectionName)
 	filter := bson.D{{postIDColumn, postID}}
-	reactionCursor, err := reactionsCollection.Find(emptyContext, filter)
-	if err != nil {
-		util.Tracer.LogError(span, err)
-		return nil, nil, err
-	}
-	likes := make([]uint, 0)
-	dislikes := make([]uint, 0)
-	for reactionCursor.Next(emptyContext) {
-		var result model.Reaction
-		err = reactionCursor.Decode(&result)
-		if err != nil {
+	for _, name := range []string{reactionsCollectionName, commentsCollectionName} {
+		if _, err := repo.getCollection(name).DeleteMany(emptyContext, filter); err != nil {
 			util.Tracer.LogError(span, err)
-			return nil, nil, err
-		}
-		if result.ReactionType == model.LIKE {
-			likes = append(likes, result.ProfileID)
-		} else if result.ReactionType == model.DISLIKE {
-			dislikes = append(dislikes, result.ProfileID)
+			return err
 		}
 	}
-	return likes, dislikes, nil
-}
-
-func (repo *PostReactionRepository) GetAllComments(ctx context.Context, postID string) ([]model.Comment, error) {
-	span := util.Tracer.StartSpanFromContext(ctx, "GetAllComments-repository")
-	defer util.Tracer.FinishSpan(span)
-
-	commentsCollection := repo.getCollection(commentsCollectionName)
-	filter := bson.D{{postIDColumn, postID}}
-	commentsCursor, err := commentsCollection.Find(emptyContext, filter)
-	if err != nil {
+	reportsFilter := bson.D{{targetTypeColumn, model.POST_TARGET}, {targetIDColumn, postID}}
+	if _, err := repo.getCollection(reportsCollectionName).DeleteMany(emptyContext, reportsFilter); err != nil {
 		util.Tracer.LogError(span, err)
-		return nil, err
+		return err
 	}
-	comments := make([]model.Comment, 0)
-	for commentsCursor.Next(emptyContext) {
-		var result model.Comment
-		err = commentsCursor.Decode(&result)
-		if err != nil {
-			util.Tracer.LogError(span, err)
-			return nil, err
-		}
-		comments = append(comments, result)
+	if _, err := repo.getCollection(countersCollectionName).DeleteOne(emptyContext, bson.D{{"_id", postID}}); err != nil {
+		util.Tracer.LogError(span, err)
+		return err
 	}
-	return comments, nil
+	return nil
 }
 
 func (repo *PostReactionRepository) getCollection(name string) *mongo.Collection {
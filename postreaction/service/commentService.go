@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	postModel "nistagram/post/model"
+	"nistagram/postreaction/dto"
+	"nistagram/postreaction/model"
+	"nistagram/util"
+	"strings"
+	"time"
+)
+
+// GetComments pages through the comments left on the post itself, each with the number of its replies.
+func (service *PostReactionService) GetComments(ctx context.Context, postID string, page postModel.Page) (dto.CommentPageDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetComments-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	comments, nextCursor, err := service.PostReactionRepository.GetComments(nextCtx, postID, primitive.NilObjectID, page)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.CommentPageDTO{}, err
+	}
+	commentIDs := make([]primitive.ObjectID, 0)
+	for _, comment := range comments {
+		commentIDs = append(commentIDs, comment.ID)
+	}
+	replyCounts, err := service.PostReactionRepository.CountReplies(nextCtx, commentIDs)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.CommentPageDTO{}, err
+	}
+	ret, err := toResponseComments(nextCtx, comments, replyCounts)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.CommentPageDTO{}, err
+	}
+	return dto.CommentPageDTO{Comments: ret, NextCursor: nextCursor}, nil
+}
+
+func (service *PostReactionService) GetReplies(ctx context.Context, commentID primitive.ObjectID, page postModel.Page) (dto.CommentPageDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetReplies-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	parent, err := service.PostReactionRepository.GetComment(nextCtx, commentID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.CommentPageDTO{}, err
+	}
+	replies, nextCursor, err := service.PostReactionRepository.GetComments(nextCtx, parent.PostID, parent.ID, page)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.CommentPageDTO{}, err
+	}
+	ret, err := toResponseComments(nextCtx, replies, nil)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.CommentPageDTO{}, err
+	}
+	return dto.CommentPageDTO{Comments: ret, NextCursor: nextCursor}, nil
+}
+
+// EditComment changes the content of a comment; only its author can edit it.
+func (service *PostReactionService) EditComment(ctx context.Context, loggedUserID uint, id primitive.ObjectID, content string) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "EditComment-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	if strings.TrimSpace(content) == "" {
+		util.Tracer.LogError(span, fmt.Errorf("empty comment"))
+		return ErrInvalidInput
+	}
+	comment, err := service.PostReactionRepository.GetComment(nextCtx, id)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if comment.ProfileID != loggedUserID {
+		util.Tracer.LogError(span, fmt.Errorf("profile %d is not the author of comment %s", loggedUserID, id.Hex()))
+		return ErrForbidden
+	}
+	if strings.Contains(content, "@") {
+		if err = canUsersBeTagged(nextCtx, content, loggedUserID); err != nil {
+			util.Tracer.LogError(span, err)
+			return ErrInvalidInput
+		}
+	}
+	return service.PostReactionRepository.UpdateComment(nextCtx, id, content, time.Now())
+}
+
+// DeleteComment removes a comment and its replies; the author of the comment and the publisher of the post can
+// delete it.
+func (service *PostReactionService) DeleteComment(ctx context.Context, loggedUserID uint, id primitive.ObjectID) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "DeleteComment-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	comment, err := service.PostReactionRepository.GetComment(nextCtx, id)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if comment.ProfileID != loggedUserID {
+		post, err := getPost(nextCtx, comment.PostID, loggedUserID)
+		if err != nil || post.PublisherId != loggedUserID {
+			util.Tracer.LogError(span, fmt.Errorf("profile %d can not delete comment %s", loggedUserID, id.Hex()))
+			return ErrForbidden
+		}
+	}
+	ids, err := service.PostReactionRepository.DeleteComment(nextCtx, comment)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	for _, deletedID := range ids {
+		if err = service.closeRemovedReports(nextCtx, model.COMMENT_TARGET, deletedID.Hex(), 0); err != nil {
+			util.Tracer.LogError(span, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// getThreadRoot finds the comment a new comment goes under; a reply to a reply joins the thread of the comment the
+// first reply was left on, as replies are only one level deep.
+func (service *PostReactionService) getThreadRoot(ctx context.Context, postID string, parentID string) (primitive.ObjectID, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "getThreadRoot-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	if parentID == "" {
+		return primitive.NilObjectID, nil
+	}
+	id, err := primitive.ObjectIDFromHex(parentID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return primitive.NilObjectID, ErrInvalidInput
+	}
+	parent, err := service.PostReactionRepository.GetComment(nextCtx, id)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return primitive.NilObjectID, ErrInvalidInput
+	}
+	if parent.PostID != postID {
+		util.Tracer.LogError(span, fmt.Errorf("comment %s is not on post %s", parentID, postID))
+		return primitive.NilObjectID, ErrInvalidInput
+	}
+	if parent.IsReply() {
+		return parent.ParentID, nil
+	}
+	return parent.ID, nil
+}
+
+func toResponseComments(ctx context.Context, comments []model.Comment, replyCounts map[primitive.ObjectID]int) ([]dto.ResponseCommentDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "toResponseComments-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	ret := make([]dto.ResponseCommentDTO, 0)
+	if len(comments) == 0 {
+		return ret, nil
+	}
+	profileIDs := make([]uint, 0)
+	for _, comment := range comments {
+		profileIDs = append(profileIDs, comment.ProfileID)
+	}
+	usernames, err := getProfileUsernamesByIDs(nextCtx, profileIDs)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	if len(usernames) != len(comments) {
+		util.Tracer.LogError(span, fmt.Errorf("bad slice sizes"))
+		return nil, fmt.Errorf("BAD_SLICE_SIZES")
+	}
+	for i, comment := range comments {
+		response := dto.ResponseCommentDTO{ID: comment.ID.Hex(), ProfileID: comment.ProfileID, Username: usernames[i],
+			Content: comment.Content, Time: comment.Time, IsEdited: comment.IsEdited(), ReplyCount: replyCounts[comment.ID]}
+		if comment.IsReply() {
+			response.ParentID = comment.ParentID.Hex()
+		}
+		ret = append(ret, response)
+	}
+	return ret, nil
+}
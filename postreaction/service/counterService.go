@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"nistagram/postreaction/dto"
+	"nistagram/postreaction/model"
+	"nistagram/util"
+)
+
+// GetPostCounts returns the counters of the posts with the reaction the viewer left on each of them, in the order
+// the posts were asked for. An anonymous viewer has the ID 0 and no reactions.
+func (service *PostReactionService) GetPostCounts(ctx context.Context, postIDs []string, viewerID uint) ([]dto.PostCountsDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetPostCounts-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	ret := make([]dto.PostCountsDTO, 0)
+	if len(postIDs) == 0 {
+		return ret, nil
+	}
+	counters, err := service.PostReactionRepository.GetCounters(nextCtx, postIDs)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	reactions := make(map[string]model.ReactionType)
+	if viewerID != 0 {
+		reactions, err = service.PostReactionRepository.GetProfileReactionTypes(nextCtx, viewerID, postIDs)
+		if err != nil {
+			util.Tracer.LogError(span, err)
+			return nil, err
+		}
+	}
+	for _, postID := range postIDs {
+		counts := dto.PostCountsDTO{PostID: postID, Reactions: make(map[string]int), Comments: counters[postID].Comments,
+			Reaction: model.GetReactionTypeString(reactions[postID])}
+		for reactionType, count := range counters[postID].Reactions {
+			if count > 0 {
+				counts.Reactions[string(reactionType)] = count
+			}
+		}
+		ret = append(ret, counts)
+	}
+	return ret, nil
+}
+
+// ReconcileCounters rebuilds the counters of all posts from their reactions and comments.
+func (service *PostReactionService) ReconcileCounters(ctx context.Context) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "ReconcileCounters-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	return service.PostReactionRepository.RebuildCounters(nextCtx)
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"nistagram/postreaction/dto"
+	"nistagram/postreaction/model"
+	"nistagram/util"
+	"sort"
+	"time"
+)
+
+// MaxInteractions bounds how many posts a profile's interactions are reported for, the ones it engaged with most.
+const MaxInteractions = 500
+
+// GetEngagement returns the like and comment counts of the posts, in the order they were asked for.
+func (service *PostReactionService) GetEngagement(ctx context.Context, postIDs []string) ([]dto.EngagementDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetEngagement-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	ret := make([]dto.EngagementDTO, 0)
+	if len(postIDs) == 0 {
+		return ret, nil
+	}
+	counters, err := service.PostReactionRepository.GetCounters(nextCtx, postIDs)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	for _, postID := range postIDs {
+		ret = append(ret, dto.EngagementDTO{PostID: postID, Likes: counters[postID].Reactions[model.LIKE],
+			Comments: counters[postID].Comments})
+	}
+	return ret, nil
+}
+
+// GetInteractions returns how many times the profile liked or commented each post it engaged with.
+func (service *PostReactionService) GetInteractions(ctx context.Context, profileID uint) ([]dto.InteractionDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetInteractions-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	likes, err := service.PostReactionRepository.CountProfileLikes(nextCtx, profileID, MaxInteractions)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	comments, err := service.PostReactionRepository.CountProfileComments(nextCtx, profileID, MaxInteractions)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	for postID, count := range comments {
+		likes[postID] += count
+	}
+	ret := make([]dto.InteractionDTO, 0)
+	for postID, count := range likes {
+		ret = append(ret, dto.InteractionDTO{PostID: postID, Count: count})
+	}
+	return ret, nil
+}
+
+// GetDailyEngagement returns the likes, dislikes and comments the post got in [from, to), for the days it got any.
+// Reactions are counted on the day they were last changed, or created when they were set before their time was kept.
+func (service *PostReactionService) GetDailyEngagement(ctx context.Context, postID string, from time.Time, to time.Time) ([]dto.DailyEngagementDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetDailyEngagement-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	reactions, err := service.PostReactionRepository.CountReactionsByDay(nextCtx, postID, from, to)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	comments, err := service.PostReactionRepository.CountCommentsByDay(nextCtx, postID, from, to)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	days := make([]string, 0)
+	for day := range reactions {
+		days = append(days, day)
+	}
+	for day := range comments {
+		if _, ok := reactions[day]; !ok {
+			days = append(days, day)
+		}
+	}
+	sort.Strings(days)
+	ret := make([]dto.DailyEngagementDTO, 0)
+	for _, day := range days {
+		ret = append(ret, dto.DailyEngagementDTO{Day: day, Likes: reactions[day][model.LIKE],
+			Dislikes: reactions[day][model.DISLIKE], Comments: comments[day]})
+	}
+	return ret, nil
+}
@@ -0,0 +1,6 @@
+package service
+
+import "errors"
+
+var ErrForbidden = errors.New("FORBIDDEN")
+var ErrInvalidInput = errors.New("INVALID_INPUT")
@@ -0,0 +1,597 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"io"
+	"net/http"
+	postModel "nistagram/post/model"
+	"nistagram/postreaction/dto"
+	"nistagram/postreaction/model"
+	"nistagram/postreaction/repository"
+	"nistagram/util"
+	"strings"
+	"time"
+)
+
+// Report files a report about a post, profile, comment or message the profile can see, keeping a copy of the target
+// as evidence. A profile has at most one open report per target and can not report what it owns.
+func (service *PostReactionService) Report(ctx context.Context, reportDTO dto.ReportDTO, loggedUserID uint) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "Report-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	category, err := getReportCategory(reportDTO.Category)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	targetType, targetID := model.POST_TARGET, reportDTO.PostID
+	if reportDTO.TargetType != "" {
+		targetType, targetID = model.GetTargetType(reportDTO.TargetType), reportDTO.TargetID
+	}
+	if targetID == "" {
+		util.Tracer.LogError(span, fmt.Errorf("report without a target"))
+		return ErrInvalidInput
+	}
+	report := model.Report{ID: primitive.NewObjectID(), TargetType: targetType, TargetID: targetID,
+		ReporterID: loggedUserID, Category: category, Reason: reportDTO.Reason, Status: model.OPEN, Time: time.Now()}
+	switch targetType {
+	case model.POST_TARGET:
+		err = describePost(nextCtx, &report)
+	case model.PROFILE_TARGET:
+		err = describeProfile(nextCtx, &report)
+	case model.COMMENT_TARGET:
+		err = service.describeComment(nextCtx, &report)
+	case model.MESSAGE_TARGET:
+		err = describeMessage(nextCtx, &report)
+	default:
+		err = ErrInvalidInput
+	}
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if report.TargetOwnerID == loggedUserID {
+		util.Tracer.LogError(span, fmt.Errorf("profile %d reported its own %s", loggedUserID, targetType.ToString()))
+		return ErrInvalidInput
+	}
+	return service.fileReport(nextCtx, &report)
+}
+
+// GetModerationQueue returns the reported targets that are not dealt with yet, the ones reported first coming first.
+func (service *PostReactionService) GetModerationQueue(ctx context.Context) ([]dto.ReportGroupDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetModerationQueue-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	groups, err := service.PostReactionRepository.GetReportGroups(nextCtx, repository.MaxModerationQueue)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+
+	postIDs := make([]string, 0)
+	for _, group := range groups {
+		if group.TargetType == model.POST_TARGET {
+			postIDs = append(postIDs, group.TargetID)
+		}
+	}
+	posts := make(map[string]dto.PostDTO)
+	if len(postIDs) > 0 {
+		found, err := getPostsByPostsIds(nextCtx, postIDs)
+		if err != nil {
+			util.Tracer.LogError(span, err)
+			return nil, err
+		}
+		for _, post := range found {
+			posts[post.ID.Hex()] = post
+		}
+	}
+
+	ret := make([]dto.ReportGroupDTO, 0)
+	for _, group := range groups {
+		groupDTO := dto.ReportGroupDTO{TargetType: group.TargetType.ToString(), TargetID: group.TargetID,
+			TargetOwnerID: group.TargetOwnerID, Status: group.Status().ToString(), ReviewerID: group.ReviewerID,
+			ReportCount: len(group.Reports), Categories: make(map[string]int), FirstReported: group.FirstReported,
+			LastReported: group.LastReported, Reports: make([]dto.ReportSummaryDTO, 0)}
+		for _, report := range group.Reports {
+			groupDTO.Categories[report.Category.ToString()]++
+			summary := dto.ReportSummaryDTO{ID: report.ID.Hex(), ReporterID: report.ReporterID,
+				Category: report.Category.ToString(), Reason: report.Reason, Time: report.Time}
+			if report.Evidence != nil {
+				summary.Evidence = &dto.EvidenceDTO{Text: report.Evidence.Text, MediaPath: report.Evidence.MediaPath,
+					PostID: report.Evidence.PostID, CreatedAt: report.Evidence.CreatedAt}
+			}
+			groupDTO.Reports = append(groupDTO.Reports, summary)
+		}
+		if post, ok := posts[group.TargetID]; ok && group.TargetType == model.POST_TARGET {
+			groupDTO.Post = &post
+			if groupDTO.TargetOwnerID == 0 {
+				groupDTO.TargetOwnerID = post.PublisherId
+			}
+		}
+		ret = append(ret, groupDTO)
+	}
+
+	ownerIDs := make([]uint, 0)
+	for _, group := range ret {
+		if group.TargetOwnerID != 0 {
+			ownerIDs = append(ownerIDs, group.TargetOwnerID)
+		}
+	}
+	if len(ownerIDs) == 0 {
+		return ret, nil
+	}
+	usernames, err := getProfileUsernamesByIDs(nextCtx, ownerIDs)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	next := 0
+	for i := range ret {
+		if ret[i].TargetOwnerID != 0 && next < len(usernames) {
+			ret[i].OwnerUsername = usernames[next]
+			next++
+		}
+	}
+	return ret, nil
+}
+
+// ReviewReports claims the open reports of the target for the moderator, so others know it is being looked at.
+func (service *PostReactionService) ReviewReports(ctx context.Context, moderatorID uint, targetType model.TargetType, targetID string, note string) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "ReviewReports-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	reports, err := service.PostReactionRepository.GetOpenReports(nextCtx, targetType, targetID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if len(reports) == 0 {
+		return mongo.ErrNoDocuments
+	}
+	if err = service.PostReactionRepository.ReviewReports(nextCtx, targetType, targetID, moderatorID); err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	return service.logModeration(nextCtx, moderatorID, model.REVIEW, reports, note)
+}
+
+// Moderate takes the action on the target of the open reports and closes them, dismissing them for DISMISS and
+// marking them actioned otherwise. Only posts and comments can be removed; warnings and bans go to the owner of the
+// target.
+func (service *PostReactionService) Moderate(ctx context.Context, moderatorID uint, targetType model.TargetType, targetID string, action model.ModerationAction, note string) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "Moderate-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	reports, err := service.PostReactionRepository.GetOpenReports(nextCtx, targetType, targetID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if len(reports) == 0 {
+		return mongo.ErrNoDocuments
+	}
+	ownerID, err := resolveTargetOwnerID(nextCtx, reports)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+
+	status := model.ACTIONED
+	switch action {
+	case model.REMOVE:
+		switch targetType {
+		case model.POST_TARGET:
+			err = removePost(nextCtx, targetID, moderatorID)
+		case model.COMMENT_TARGET:
+			err = service.removeComment(nextCtx, targetID, moderatorID)
+		default:
+			util.Tracer.LogError(span, fmt.Errorf("cannot remove target of type %s", targetType.ToString()))
+			return ErrInvalidInput
+		}
+	case model.WARN:
+		if ownerID == 0 {
+			return ErrInvalidInput
+		}
+		err = warnProfile(nextCtx, ownerID, note)
+	case model.BAN:
+		if ownerID == 0 {
+			return ErrInvalidInput
+		}
+		err = banProfile(nextCtx, ownerID)
+	case model.DISMISS:
+		status = model.DISMISSED
+	default:
+		return ErrInvalidInput
+	}
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if err = service.closeReports(nextCtx, reports, status, moderatorID, action); err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	return service.logModeration(nextCtx, moderatorID, action, reports, note)
+}
+
+// CloseRemovedPostReports closes the open reports of a post its publisher or an admin deleted outside of moderation.
+func (service *PostReactionService) CloseRemovedPostReports(ctx context.Context, postID string) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "CloseRemovedPostReports-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	return service.closeRemovedReports(nextCtx, model.POST_TARGET, postID, 0)
+}
+
+// closeRemovedReports closes the open reports of a removed target; the moderator is 0 for targets removed outside of
+// moderation.
+func (service *PostReactionService) closeRemovedReports(ctx context.Context, targetType model.TargetType, targetID string, moderatorID uint) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "closeRemovedReports-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	reports, err := service.PostReactionRepository.GetOpenReports(nextCtx, targetType, targetID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if len(reports) == 0 {
+		return nil
+	}
+	if err = service.closeReports(nextCtx, reports, model.ACTIONED, moderatorID, model.REMOVE); err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	return service.logModeration(nextCtx, moderatorID, model.REMOVE, reports, "")
+}
+
+func (service *PostReactionService) GetModerationLog(ctx context.Context, targetType model.TargetType, targetID string, moderatorID uint, page postModel.Page) (dto.ModerationLogPageDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetModerationLog-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	entries, nextCursor, err := service.PostReactionRepository.GetLogEntries(nextCtx, targetType, targetID, moderatorID, page)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.ModerationLogPageDTO{}, err
+	}
+	ret := dto.ModerationLogPageDTO{Entries: make([]dto.ModerationLogEntryDTO, 0), NextCursor: nextCursor}
+	for _, entry := range entries {
+		reportIDs := make([]string, 0)
+		for _, id := range entry.ReportIDs {
+			reportIDs = append(reportIDs, id.Hex())
+		}
+		ret.Entries = append(ret.Entries, dto.ModerationLogEntryDTO{ID: entry.ID.Hex(), ModeratorID: entry.ModeratorID,
+			Action: entry.Action.ToString(), TargetType: entry.TargetType.ToString(), TargetID: entry.TargetID,
+			TargetOwnerID: entry.TargetOwnerID, ReportIDs: reportIDs, Note: entry.Note, Time: entry.Time})
+	}
+	return ret, nil
+}
+
+func (service *PostReactionService) BackfillReports(ctx context.Context) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "BackfillReports-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	return service.PostReactionRepository.BackfillReports(nextCtx)
+}
+
+func (service *PostReactionService) fileReport(ctx context.Context, report *model.Report) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "fileReport-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	reported, err := service.PostReactionRepository.HasOpenReport(nextCtx, report.ReporterID, report.TargetType, report.TargetID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if reported {
+		util.Tracer.LogError(span, fmt.Errorf("profile %d already reported %s %s", report.ReporterID,
+			report.TargetType.ToString(), report.TargetID))
+		return ErrInvalidInput
+	}
+	return service.PostReactionRepository.CreateReport(nextCtx, report)
+}
+
+func (service *PostReactionService) closeReports(ctx context.Context, reports []model.Report, status model.ReportStatus, moderatorID uint, action model.ModerationAction) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "closeReports-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	ids := make([]primitive.ObjectID, 0)
+	for _, report := range reports {
+		ids = append(ids, report.ID)
+	}
+	return service.PostReactionRepository.CloseReports(nextCtx, ids, status, moderatorID, action, time.Now())
+}
+
+func (service *PostReactionService) logModeration(ctx context.Context, moderatorID uint, action model.ModerationAction, reports []model.Report, note string) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "logModeration-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	entry := model.ModerationLogEntry{ID: primitive.NewObjectID(), ModeratorID: moderatorID, Action: action,
+		TargetType: reports[0].TargetType, TargetID: reports[0].TargetID, TargetOwnerID: getTargetOwnerID(reports),
+		ReportIDs: make([]primitive.ObjectID, 0), Note: note, Time: time.Now()}
+	for _, report := range reports {
+		entry.ReportIDs = append(entry.ReportIDs, report.ID)
+	}
+	return service.PostReactionRepository.CreateLogEntry(nextCtx, &entry)
+}
+
+// getReportCategory reads the category a reporter picked, reports without one being OTHER.
+func getReportCategory(value string) (model.ReportCategory, error) {
+	if strings.TrimSpace(value) == "" {
+		return model.OTHER, nil
+	}
+	category := model.GetReportCategory(strings.TrimSpace(value))
+	if category == model.NO_CATEGORY {
+		return category, ErrInvalidInput
+	}
+	return category, nil
+}
+
+// getTargetOwnerID returns the owner stored with the reports; reports filed before moderation do not have one.
+func getTargetOwnerID(reports []model.Report) uint {
+	for _, report := range reports {
+		if report.TargetOwnerID != 0 {
+			return report.TargetOwnerID
+		}
+	}
+	return 0
+}
+
+// describePost fills in the publisher of a post the reporter can see and a copy of its description.
+func describePost(ctx context.Context, report *model.Report) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "describePost-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	post, err := getPost(nextCtx, report.TargetID, report.ReporterID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if post.IsDeleted {
+		util.Tracer.LogError(span, fmt.Errorf("cannot report deleted post"))
+		return ErrInvalidInput
+	}
+	report.TargetOwnerID = post.PublisherId
+	report.Evidence = &model.ReportEvidence{Text: post.Description, PostID: report.TargetID, CreatedAt: post.PublishDate}
+	if len(post.Medias) > 0 {
+		report.Evidence.MediaPath = post.Medias[0].FilePath
+	}
+	return nil
+}
+
+// describeProfile keeps the username and biography of the reported profile, which is its own owner.
+func describeProfile(ctx context.Context, report *model.Report) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "describeProfile-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	profileID := util.String2Uint(report.TargetID)
+	if profileID == 0 {
+		return ErrInvalidInput
+	}
+	profile := util.GetProfile(nextCtx, profileID)
+	if profile == nil || profile.ID != profileID {
+		util.Tracer.LogError(span, fmt.Errorf("profile %d not found", profileID))
+		return mongo.ErrNoDocuments
+	}
+	report.TargetOwnerID = profileID
+	report.Evidence = &model.ReportEvidence{Text: strings.TrimSpace(profile.Username + "\n" + profile.Biography),
+		CreatedAt: profile.CreatedAt}
+	return nil
+}
+
+// describeComment keeps the content of a comment on a post the reporter can see.
+func (service *PostReactionService) describeComment(ctx context.Context, report *model.Report) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "describeComment-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	id, err := primitive.ObjectIDFromHex(report.TargetID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return ErrInvalidInput
+	}
+	comment, err := service.PostReactionRepository.GetComment(nextCtx, id)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if _, err = getPost(nextCtx, comment.PostID, report.ReporterID); err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	report.TargetOwnerID = comment.ProfileID
+	report.Evidence = &model.ReportEvidence{Text: comment.Content, PostID: comment.PostID, CreatedAt: comment.Time}
+	return nil
+}
+
+// describeMessage keeps the text and media of a direct message. The notification service only hands it out to the
+// sender and the receiver, so nobody else can report a conversation they are not in.
+func describeMessage(ctx context.Context, report *model.Report) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "describeMessage-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	notificationHost, notificationPort := util.GetNotificationHostAndPort()
+	resp, err := util.CrossServiceRequest(nextCtx, http.MethodGet,
+		util.GetCrossServiceProtocol()+"://"+notificationHost+":"+notificationPort+"/message/"+report.TargetID+
+			"/participant/"+util.Uint2String(report.ReporterID),
+		nil, map[string]string{})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return mongo.ErrNoDocuments
+	case http.StatusForbidden:
+		return ErrForbidden
+	default:
+		return fmt.Errorf("reading message %s failed with status %d", report.TargetID, resp.StatusCode)
+	}
+
+	var message struct {
+		SenderId  uint      `json:"senderId"`
+		Text      string    `json:"text"`
+		Timestamp time.Time `json:"timestamp"`
+		MediaPath string    `json:"mediaPath"`
+		PostId    string    `json:"postId"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&message); err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	report.TargetOwnerID = message.SenderId
+	report.Evidence = &model.ReportEvidence{Text: message.Text, MediaPath: message.MediaPath, PostID: message.PostId,
+		CreatedAt: message.Timestamp}
+	return nil
+}
+
+// removeComment deletes a reported comment with its replies and closes the reports of the replies; a comment its
+// author already deleted counts as removed.
+func (service *PostReactionService) removeComment(ctx context.Context, commentID string, moderatorID uint) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "removeComment-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	id, err := primitive.ObjectIDFromHex(commentID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return ErrInvalidInput
+	}
+	comment, err := service.PostReactionRepository.GetComment(nextCtx, id)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	ids, err := service.PostReactionRepository.DeleteComment(nextCtx, comment)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	for _, deletedID := range ids {
+		if deletedID == id {
+			continue
+		}
+		if err = service.closeRemovedReports(nextCtx, model.COMMENT_TARGET, deletedID.Hex(), moderatorID); err != nil {
+			util.Tracer.LogError(span, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveTargetOwnerID returns the owner stored with the reports. Reports of posts filed before moderation do not
+// have one, so it is read from the post and set on the reports, which keeps it in the moderation log as well.
+func resolveTargetOwnerID(ctx context.Context, reports []model.Report) (uint, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "resolveTargetOwnerID-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	ownerID := getTargetOwnerID(reports)
+	if ownerID != 0 || reports[0].TargetType != model.POST_TARGET {
+		return ownerID, nil
+	}
+	posts, err := getPostsByPostsIds(nextCtx, []string{reports[0].TargetID})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return 0, err
+	}
+	if len(posts) == 0 {
+		return 0, nil
+	}
+	for i := range reports {
+		reports[i].TargetOwnerID = posts[0].PublisherId
+	}
+	return posts[0].PublisherId, nil
+}
+
+func removePost(ctx context.Context, postID string, moderatorID uint) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "removePost-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	postHost, postPort := util.GetPostHostAndPort()
+	resp, err := util.CrossServiceRequest(nextCtx, http.MethodDelete,
+		util.GetCrossServiceProtocol()+"://"+postHost+":"+postPort+"/moderation/"+postID+"?moderator="+util.Uint2String(moderatorID),
+		nil, map[string]string{})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if resp.StatusCode != 200 {
+		util.Tracer.LogError(span, fmt.Errorf("removing post %s failed with status %d", postID, resp.StatusCode))
+		return fmt.Errorf("BAD_POST_ID")
+	}
+	return nil
+}
+
+func warnProfile(ctx context.Context, profileID uint, reason string) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "warnProfile-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	type data struct {
+		Reason string `json:"reason"`
+	}
+	jsonBody, err := json.Marshal(data{Reason: reason})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	authHost, authPort := util.GetAuthHostAndPort()
+	resp, err := util.CrossServiceRequest(nextCtx, http.MethodPost,
+		util.GetCrossServiceProtocol()+"://"+authHost+":"+authPort+"/warn/"+util.Uint2String(profileID),
+		jsonBody, map[string]string{"Content-Type": "application/json;"})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if resp.StatusCode != 200 {
+		util.Tracer.LogError(span, fmt.Errorf("warning profile %d failed with status %d", profileID, resp.StatusCode))
+		return fmt.Errorf("BAD_PROFILE_ID")
+	}
+	return nil
+}
+
+func banProfile(ctx context.Context, profileID uint) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "banProfile-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+	authHost, authPort := util.GetAuthHostAndPort()
+	resp, err := util.CrossServiceRequest(nextCtx, http.MethodDelete,
+		util.GetCrossServiceProtocol()+"://"+authHost+":"+authPort+"/ban/"+util.Uint2String(profileID),
+		nil, map[string]string{})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	if resp.StatusCode != 200 {
+		util.Tracer.LogError(span, fmt.Errorf("banning profile %d failed with status %d", profileID, resp.StatusCode))
+		return fmt.Errorf("BAD_PROFILE_ID")
+	}
+	return nil
+}
@@ -26,7 +26,7 @@ func (service *PostReactionService) ReactOnPost(ctx context.Context, reactionDto
 	defer util.Tracer.FinishSpan(span)
 
 	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
-	post, err := getPost(nextCtx, reactionDto.PostID)
+	post, err := getPost(nextCtx, reactionDto.PostID, loggedUserID)
 	if err != nil {
 		util.Tracer.LogError(span, err)
 		return err
@@ -39,7 +39,8 @@ func (service *PostReactionService) ReactOnPost(ctx context.Context, reactionDto
 		util.Tracer.LogError(span, fmt.Errorf("cannot react on deleted post"))
 		return fmt.Errorf("CANNOT REACT ON DELETED POST")
 	}
-	reaction := model.Reaction{ReactionType: reactionType, PostID: reactionDto.PostID, ProfileID: loggedUserID}
+	reaction := model.Reaction{ReactionType: reactionType, PostID: reactionDto.PostID, ProfileID: loggedUserID,
+		Time: time.Now()}
 	oldReaction, err := service.PostReactionRepository.ReactOnPost(nextCtx, &reaction)
 	if err != nil {
 		util.Tracer.LogError(span, err)
@@ -47,15 +48,10 @@ func (service *PostReactionService) ReactOnPost(ctx context.Context, reactionDto
 	}
 	if oldReaction != nil && *oldReaction != reactionType {
 		go func() {
-			var eventType string
-			if *oldReaction == model.LIKE {
-				eventType = "LIKE_RESET"
-			} else {
-				eventType = "DISLIKE_RESET"
-			}
-			event := &dto.EventDTO{EventType: eventType, PostId: reactionDto.PostID,
+			event := &dto.EventDTO{EventType: reactionEventType(*oldReaction, true), PostId: reactionDto.PostID,
 				ProfileId: loggedUserID, CampaignId: reactionDto.CampaignID,
-				InfluencerId: reactionDto.InfluencerID, InfluencerUsername: reactionDto.InfluencerUsername}
+				InfluencerId: reactionDto.InfluencerID, InfluencerUsername: reactionDto.InfluencerUsername,
+				Reaction: string(*oldReaction)}
 			if reactionDto.InfluencerID != 0 {
 				err = saveToMonitoringMsInfluencer(nextCtx, event)
 			} else {
@@ -70,9 +66,10 @@ func (service *PostReactionService) ReactOnPost(ctx context.Context, reactionDto
 	}
 	if reactionDto.CampaignID != 0 {
 		go func() {
-			event := &dto.EventDTO{EventType: reactionDto.ReactionType, PostId: reactionDto.PostID,
+			event := &dto.EventDTO{EventType: reactionEventType(reactionType, false), PostId: reactionDto.PostID,
 				ProfileId: loggedUserID, CampaignId: reactionDto.CampaignID,
-				InfluencerId: reactionDto.InfluencerID, InfluencerUsername: reactionDto.InfluencerUsername}
+				InfluencerId: reactionDto.InfluencerID, InfluencerUsername: reactionDto.InfluencerUsername,
+				Reaction: string(reactionType)}
 			if reactionDto.InfluencerID != 0 {
 				err = saveToMonitoringMsInfluencer(nextCtx, event)
 			} else {
@@ -97,15 +94,10 @@ func (service *PostReactionService) DeleteReaction(ctx context.Context, reaction
 		return err
 	}
 	go func() {
-		var eventType string
-		if deletedReaction.ReactionType == model.LIKE {
-			eventType = "LIKE_RESET"
-		} else {
-			eventType = "DISLIKE_RESET"
-		}
-		event := &dto.EventDTO{EventType: eventType, PostId: deletedReaction.PostID,
-			ProfileId: loggedUserID, CampaignId: reactionDTO.CampaignID,
-			InfluencerId: reactionDTO.InfluencerID, InfluencerUsername: reactionDTO.InfluencerUsername}
+		event := &dto.EventDTO{EventType: reactionEventType(deletedReaction.ReactionType, true),
+			PostId: deletedReaction.PostID, ProfileId: loggedUserID, CampaignId: reactionDTO.CampaignID,
+			InfluencerId: reactionDTO.InfluencerID, InfluencerUsername: reactionDTO.InfluencerUsername,
+			Reaction: string(deletedReaction.ReactionType)}
 		if reactionDTO.InfluencerID != 0 {
 			err = saveToMonitoringMsInfluencer(nextCtx, event)
 		} else {
@@ -125,7 +117,7 @@ func (service *PostReactionService) CommentPost(ctx context.Context, commentDTO
 	defer util.Tracer.FinishSpan(span)
 
 	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
-	post, err := getPost(nextCtx, commentDTO.PostID)
+	post, err := getPost(nextCtx, commentDTO.PostID, loggedUserID)
 	if err != nil {
 		util.Tracer.LogError(span, err)
 		return err
@@ -145,8 +137,13 @@ func (service *PostReactionService) CommentPost(ctx context.Context, commentDTO
 			return err
 		}
 	}
-	comment := model.Comment{PostID: commentDTO.PostID, ProfileID: loggedUserID,
-		Content: commentDTO.Content, Time: time.Now()}
+	parentID, err := service.getThreadRoot(nextCtx, commentDTO.PostID, commentDTO.ParentID)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return err
+	}
+	comment := model.Comment{ID: primitive.NewObjectID(), PostID: commentDTO.PostID, ParentID: parentID,
+		ProfileID: loggedUserID, Content: commentDTO.Content, Time: time.Now()}
 	err = service.PostReactionRepository.CommentPost(nextCtx, &comment)
 	if err != nil {
 		util.Tracer.LogError(span, err)
@@ -173,25 +170,6 @@ func (service *PostReactionService) CommentPost(ctx context.Context, commentDTO
 	return nil
 }
 
-func (service *PostReactionService) ReportPost(ctx context.Context, postID string, reason string) error {
-	span := util.Tracer.StartSpanFromContext(ctx, "ReportPost-service")
-	defer util.Tracer.FinishSpan(span)
-
-	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
-	post, err := getPost(nextCtx, postID)
-	if err != nil {
-		util.Tracer.LogError(span, err)
-		return err
-	}
-	if post.IsDeleted {
-		util.Tracer.LogError(span, fmt.Errorf("cannot report deleted post"))
-		return fmt.Errorf("CANNOT REPORT DELETED POST")
-	}
-	report := model.Report{ID: primitive.NewObjectID(), PostID: postID, Time: time.Now(), Reason: reason,
-		IsDeleted: false}
-	return service.PostReactionRepository.ReportPost(nextCtx, &report)
-}
-
 func (service *PostReactionService) GetMyReactions(ctx context.Context, reactionType model.ReactionType, loggedUserID uint) ([]postModel.Post, error) {
 	span := util.Tracer.StartSpanFromContext(ctx, "GetMyReactions-service")
 	defer util.Tracer.FinishSpan(span)
@@ -211,7 +189,7 @@ func (service *PostReactionService) GetMyReactions(ctx context.Context, reaction
 		"ids": ret,
 	})
 	resp, err := util.CrossServiceRequest(nextCtx, http.MethodPost,
-		util.GetCrossServiceProtocol()+"://"+postHost+":"+postPort+"/posts",
+		util.GetCrossServiceProtocol()+"://"+postHost+":"+postPort+"/posts?viewer="+util.Uint2String(loggedUserID),
 		postBody, map[string]string{"Content-Type": "application/json;"})
 
 	if err != nil {
@@ -241,127 +219,12 @@ func (service *PostReactionService) GetMyReactions(ctx context.Context, reaction
 	return posts, nil
 }
 
-func (service *PostReactionService) GetReactionTypes(ctx context.Context, profileID uint, postIDs []string) []string {
-	span := util.Tracer.StartSpanFromContext(ctx, "GetReactionTypes-service")
-	defer util.Tracer.FinishSpan(span)
-
-	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
-	ret := make([]string, 0)
-	for _, value := range postIDs {
-		ret = append(ret, service.PostReactionRepository.GetReactionType(nextCtx, profileID, value))
-	}
-	return ret
-}
-
-func (service *PostReactionService) GetAllReports(ctx context.Context) ([]dto.ShowReportDTO, error) {
-	span := util.Tracer.StartSpanFromContext(ctx, "GetAllReports-service")
-	defer util.Tracer.FinishSpan(span)
-
-	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
-	var ret []dto.ShowReportDTO
-
-	reports, err := service.PostReactionRepository.GetAllReports(nextCtx)
-	if err != nil {
-		util.Tracer.LogError(span, err)
-		return nil, err
-	}
-
-	var repIds []string
-
-	for i := 0; i < len(reports); i++ {
-		repIds = append(repIds, reports[i].PostID)
-	}
-
-	posts, err := getPostsByPostsIds(nextCtx, repIds)
-	if err != nil {
-		util.Tracer.LogError(span, err)
-		return nil, err
-	}
-
-	for i := 0; i < len(posts); i++ {
-		retDto := dto.ShowReportDTO{ReportID: util.GetStringIDFromMongoID(reports[i].ID),
-			PostID: util.GetStringIDFromMongoID(posts[i].ID), Reason: reports[i].Reason,
-			PublisherId: posts[i].PublisherId, PublisherUsername: posts[i].PublisherUsername,
-			Medias: posts[i].Medias, Description: posts[i].Description}
-		ret = append(ret, retDto)
-	}
-
-	return ret, nil
-}
-
-func (service *PostReactionService) DeletePostsReports(ctx context.Context, postId string) error {
-	span := util.Tracer.StartSpanFromContext(ctx, "DeletePostsReports-service")
-	defer util.Tracer.FinishSpan(span)
-
-	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
-	reports, err := service.PostReactionRepository.GetReportsByPostId(nextCtx, postId)
-	if err != nil {
-		util.Tracer.LogError(span, err)
-		return err
-	}
-
-	for i := 0; i < len(reports); i++ {
-		err = service.PostReactionRepository.DeleteReport(nextCtx, reports[i].ID)
-		if err != nil {
-			util.Tracer.LogError(span, err)
-			return err
-		}
-	}
-
-	return nil
-}
-
-func (service *PostReactionService) GetAllReactions(ctx context.Context, postID string) ([]string, []string, error) {
-	span := util.Tracer.StartSpanFromContext(ctx, "GetAllReactions-service")
-	defer util.Tracer.FinishSpan(span)
-
-	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
-	likes, dislikes, err := service.PostReactionRepository.GetAllReactions(nextCtx, postID)
-	if err != nil {
-		util.Tracer.LogError(span, err)
-		return nil, nil, err
-	}
-	likesUsernames, err := getProfileUsernamesByIDs(nextCtx, likes)
-	if err != nil {
-		util.Tracer.LogError(span, err)
-		return nil, nil, err
-	}
-	dislikesUsernames, err := getProfileUsernamesByIDs(nextCtx, dislikes)
-	if err != nil {
-		util.Tracer.LogError(span, err)
-		return nil, nil, err
-	}
-	return likesUsernames, dislikesUsernames, nil
-}
-
-func (service *PostReactionService) GetAllComments(ctx context.Context, postID string) ([]dto.ResponseCommentDTO, error) {
-	span := util.Tracer.StartSpanFromContext(ctx, "GetAllComments-service")
+func (service *PostReactionService) DeletePostData(ctx context.Context, postId string) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "DeletePostData-service")
 	defer util.Tracer.FinishSpan(span)
 
 	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
-	comments, err := service.PostReactionRepository.GetAllComments(nextCtx, postID)
-	if err != nil {
-		util.Tracer.LogError(span, err)
-		return nil, err
-	}
-	profileIDs := make([]uint, 0)
-	for _, value := range comments {
-		profileIDs = append(profileIDs, value.ProfileID)
-	}
-	commentUsernames, err := getProfileUsernamesByIDs(nextCtx, profileIDs)
-	if err != nil {
-		util.Tracer.LogError(span, err)
-		return nil, err
-	}
-	ret := make([]dto.ResponseCommentDTO, 0)
-	if len(commentUsernames) != len(comments) {
-		util.Tracer.LogError(span, fmt.Errorf("bad slice sizes"))
-		return nil, fmt.Errorf("BAD_SLICE_SIZES")
-	}
-	for i, value := range comments {
-		ret = append(ret, dto.ResponseCommentDTO{Content: value.Content, Username: commentUsernames[i]})
-	}
-	return ret, nil
+	return service.PostReactionRepository.DeletePostData(nextCtx, postId)
 }
 
 func getProfileUsernamesByIDs(ctx context.Context, profileIDs []uint) ([]string, error) {
@@ -453,14 +316,15 @@ func getPostsByPostsIds(ctx context.Context, postsIds []string) ([]dto.PostDTO,
 	return ret, nil
 }
 
-func getPost(ctx context.Context, postID string) (*postModel.Post, error) {
+// getPost reads a post as the viewer sees it, so hidden posts can not be reacted on, commented or reported.
+func getPost(ctx context.Context, postID string, viewerID uint) (*postModel.Post, error) {
 	span := util.Tracer.StartSpanFromContext(ctx, "getPost-service")
 	defer util.Tracer.FinishSpan(span)
 
 	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
 	postHost, postPort := util.GetPostHostAndPort()
 	resp, err := util.CrossServiceRequest(nextCtx, http.MethodGet,
-		util.GetCrossServiceProtocol()+"://"+postHost+":"+postPort+"/post/"+postID,
+		util.GetCrossServiceProtocol()+"://"+postHost+":"+postPort+"/post/"+postID+"?viewer="+util.Uint2String(viewerID),
 		nil, map[string]string{})
 	if err != nil {
 		util.Tracer.LogError(span, err)
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	postModel "nistagram/post/model"
+	"nistagram/postreaction/dto"
+	"nistagram/postreaction/model"
+	"nistagram/util"
+)
+
+// GetReactionCounts returns how many profiles left each offered reaction on the post, in the configured order.
+func (service *PostReactionService) GetReactionCounts(ctx context.Context, postID string) ([]dto.ReactionCountDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetReactionCounts-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	counters, err := service.PostReactionRepository.GetCounters(nextCtx, []string{postID})
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return nil, err
+	}
+	ret := make([]dto.ReactionCountDTO, 0)
+	for _, option := range model.GetReactionOptions() {
+		ret = append(ret, dto.ReactionCountDTO{Type: string(option.Type), Emoji: option.Emoji,
+			Count: counters[postID].Reactions[option.Type]})
+	}
+	return ret, nil
+}
+
+// GetReactors pages through the profiles that left the reaction on the post, the latest first.
+func (service *PostReactionService) GetReactors(ctx context.Context, postID string, reactionType model.ReactionType, page postModel.Page) (dto.ReactorPageDTO, error) {
+	span := util.Tracer.StartSpanFromContext(ctx, "GetReactors-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	reactions, nextCursor, err := service.PostReactionRepository.GetReactions(nextCtx, postID, reactionType, page)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.ReactorPageDTO{}, err
+	}
+	ret := dto.ReactorPageDTO{Reactors: make([]dto.ReactorDTO, 0), NextCursor: nextCursor}
+	if len(reactions) == 0 {
+		return ret, nil
+	}
+	profileIDs := make([]uint, 0)
+	for _, reaction := range reactions {
+		profileIDs = append(profileIDs, reaction.ProfileID)
+	}
+	usernames, err := getProfileUsernamesByIDs(nextCtx, profileIDs)
+	if err != nil {
+		util.Tracer.LogError(span, err)
+		return dto.ReactorPageDTO{}, err
+	}
+	if len(usernames) != len(reactions) {
+		util.Tracer.LogError(span, fmt.Errorf("bad slice sizes"))
+		return dto.ReactorPageDTO{}, fmt.Errorf("BAD_SLICE_SIZES")
+	}
+	for i, reaction := range reactions {
+		ret.Reactors = append(ret.Reactors, dto.ReactorDTO{ProfileID: reaction.ProfileID, Username: usernames[i],
+			Time: reaction.Time})
+	}
+	return ret, nil
+}
+
+func (service *PostReactionService) BackfillReactions(ctx context.Context) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "BackfillReactions-service")
+	defer util.Tracer.FinishSpan(span)
+
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	return service.PostReactionRepository.BackfillReactions(nextCtx)
+}
+
+// reactionEventType is the monitoring event a reaction is recorded as. Likes and dislikes keep the events campaigns
+// were built on; the other reactions are recorded as REACTION events that carry the name of the reaction.
+func reactionEventType(reactionType model.ReactionType, reset bool) string {
+	eventType := "REACTION"
+	switch reactionType {
+	case model.LIKE:
+		eventType = "LIKE"
+	case model.DISLIKE:
+		eventType = "DISLIKE"
+	}
+	if reset {
+		return eventType + "_RESET"
+	}
+	return eventType
+}
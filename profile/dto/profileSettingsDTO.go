@@ -4,4 +4,5 @@ type ProfileSettingsDTO struct {
 	IsPrivate                    bool `json:"isPrivate"`
 	CanReceiveMessageFromUnknown bool `json:"canReceiveMessageFromUnknown"`
 	CanBeTagged                  bool `json:"canBeTagged"`
+	ShowSensitiveContent         bool `json:"showSensitiveContent"`
 }
@@ -8,8 +8,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"gopkg.in/go-playground/validator.v9"
+	"gorm.io/gorm"
 	"html/template"
-	"io"
 	"mime/multipart"
 	"net/http"
 	"nistagram/profile/dto"
@@ -253,21 +253,10 @@ func (handler *Handler) CreateVerificationRequest(w http.ResponseWriter, r *http
 		return
 	}
 
-	f, err := os.OpenFile("../../nistagramstaticdata/data/"+fileName, os.O_WRONLY|os.O_CREATE, 0666)
-	defer func(f *os.File) {
-		_ = f.Close()
-	}(f)
 	defer func(picture multipart.File) {
 		_ = picture.Close()
 	}(picture)
-	if err != nil {
-		util.Tracer.LogError(span, err)
-		fmt.Println(err)
-		w.WriteHeader(http.StatusInternalServerError)
-		_, _ = w.Write([]byte("{\"message\":\"error\"}"))
-		return
-	}
-	_, err = io.Copy(f, picture)
+	err = handler.ProfileService.SaveVerificationImage(ctx, fileName, picture, picHeader.Size, picHeader.Header.Get("Content-Type"))
 	if err != nil {
 		util.Tracer.LogError(span, err)
 		fmt.Println(err)
@@ -333,6 +322,11 @@ func (handler *Handler) GetProfileByUsername(w http.ResponseWriter, r *http.Requ
 
 	vars := mux.Vars(r)
 	result, err := handler.ProfileService.GetProfileByUsername(ctx, template.HTMLEscapeString(vars["username"]))
+	if err == gorm.ErrRecordNotFound {
+		util.Tracer.LogError(span, err)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		util.Tracer.LogError(span, err)
 		fmt.Println(err)
@@ -9,6 +9,7 @@ import (
 	"nistagram/profile/repository"
 	"nistagram/profile/service"
 	"nistagram/util"
+	"nistagram/util/mediastore"
 	"nistagram/util/saga"
 	"os"
 	"time"
@@ -101,7 +102,8 @@ func initProfileRepo(database *gorm.DB, client *redis.Client) *repository.Profil
 }
 
 func initService(profileRepo *repository.ProfileRepository) *service.ProfileService {
-	return &service.ProfileService{ProfileRepository: profileRepo}
+	return &service.ProfileService{ProfileRepository: profileRepo,
+		MediaStore: mediastore.NewFromEnv("../../nistagramstaticdata/data", "/static/data")}
 }
 
 func initHandler(service *service.ProfileService) *handler.Handler {
@@ -137,7 +139,7 @@ func handleFunc(handler *handler.Handler) {
 	router.HandleFunc("/verification-requests",
 		util.RBAC(handler.GetVerificationRequests, "READ_VERIFICATION_REQUESTS", true)).Methods("GET") // frontend func
 	router.HandleFunc("/get-by-id/{id}",
-		util.MSAuth(handler.GetProfileByID, []string{"connection", "post"})).Methods("GET")
+		util.MSAuth(handler.GetProfileByID, []string{"connection", "post", "postreaction"})).Methods("GET")
 	router.HandleFunc("/get-by-ids",
 		util.MSAuth(handler.GetProfileUsernamesByIDs, []string{"postreaction", "monitoring", "post", "notification"})).Methods("POST")
 	router.HandleFunc("/personal-data/{id}",
@@ -7,5 +7,6 @@ type ProfileSettings struct {
 	IsPrivate                    bool `json:"isPrivate"`
 	CanReceiveMessageFromUnknown bool `json:"canReceiveMessageFromUnknown"`
 	CanBeTagged                  bool `json:"canBeTagged"`
+	ShowSensitiveContent         bool `json:"showSensitiveContent"`
 	ProfileID                    uint
 }
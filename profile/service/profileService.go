@@ -5,16 +5,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"gorm.io/gorm"
+	"io"
 	"net/http"
 	"nistagram/profile/dto"
 	"nistagram/profile/model"
 	"nistagram/profile/repository"
 	"nistagram/util"
+	"nistagram/util/mediastore"
 	"nistagram/util/saga"
 )
 
 type ProfileService struct {
 	ProfileRepository *repository.ProfileRepository
+	MediaStore        mediastore.MediaStore
 }
 
 func (service *ProfileService) Register(ctx context.Context, dto dto.RegistrationDto) error {
@@ -220,6 +223,7 @@ func (service *ProfileService) ChangeProfileSettings(ctx context.Context, dto dt
 	profileSettings.IsPrivate = dto.IsPrivate
 	profileSettings.CanBeTagged = dto.CanBeTagged
 	profileSettings.CanReceiveMessageFromUnknown = dto.CanReceiveMessageFromUnknown
+	profileSettings.ShowSensitiveContent = dto.ShowSensitiveContent
 	err = service.ProfileRepository.UpdateProfileSettings(nextCtx, profileSettings)
 	if err != nil{
 		return err
@@ -335,6 +339,15 @@ func (service *ProfileService) CreateVerificationRequest(ctx context.Context, pr
 	return err
 }
 
+func (service *ProfileService) SaveVerificationImage(ctx context.Context, fileName string, content io.Reader, size int64, contentType string) error {
+	span := util.Tracer.StartSpanFromContext(ctx, "SaveVerificationImage-service")
+	defer util.Tracer.FinishSpan(span)
+	util.Tracer.LogFields(span, "service", fmt.Sprintf("servicing file %v\n", fileName))
+	nextCtx := util.Tracer.ContextWithSpan(ctx, span)
+
+	return service.MediaStore.Put(nextCtx, fileName, content, size, contentType)
+}
+
 func (service *ProfileService) UpdateVerificationRequest(ctx context.Context, verifyDTO dto.VerifyDTO) error {
 	span := util.Tracer.StartSpanFromContext(ctx, "UpdateVerificationRequest-service")
 	defer util.Tracer.FinishSpan(span)
@@ -382,6 +395,7 @@ func (service *ProfileService) GetMyProfileSettings(ctx context.Context, loggedU
 	ret.CanReceiveMessageFromUnknown = profile.ProfileSettings.CanReceiveMessageFromUnknown
 	ret.CanBeTagged = profile.ProfileSettings.CanBeTagged
 	ret.IsPrivate = profile.ProfileSettings.IsPrivate
+	ret.ShowSensitiveContent = profile.ProfileSettings.ShowSensitiveContent
 	return ret, nil
 }
 
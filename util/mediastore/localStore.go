@@ -0,0 +1,72 @@
+package mediastore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LocalStore keeps media in a directory that the static file server exposes under BaseURL.
+type LocalStore struct {
+	Directory string
+	BaseURL   string
+}
+
+func (store *LocalStore) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	path, err := store.path(key)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(f, content); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (store *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := store.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (store *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := store.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(path); os.IsNotExist(err) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// SignedURL returns the public URL of the file; the static file server does not check signatures, so expiry is ignored.
+func (store *LocalStore) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if _, err := store.path(key); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(store.BaseURL, "/") + "/" + url.PathEscape(key), nil
+}
+
+func (store *LocalStore) path(key string) (string, error) {
+	if key == "" || key != filepath.Base(key) {
+		return "", fmt.Errorf("invalid media key %q", key)
+	}
+	return filepath.Join(store.Directory, key), nil
+}
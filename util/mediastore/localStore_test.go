@@ -0,0 +1,112 @@
+package mediastore
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalStoreRoundTrip(t *testing.T) {
+	store := &LocalStore{Directory: t.TempDir(), BaseURL: "https://localhost:83/static/data/"}
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		key     string
+		content string
+		url     string
+	}{
+		{"image", "5f2b0c1e-photo.jpg", "not really a jpeg", "https://localhost:83/static/data/5f2b0c1e-photo.jpg"},
+		{"key that needs escaping", "summer trip (1).mp4", "not really a video",
+			"https://localhost:83/static/data/summer%20trip%20%281%29.mp4"},
+		{"empty file", "empty.txt", "", "https://localhost:83/static/data/empty.txt"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := store.Put(ctx, test.key, strings.NewReader(test.content), int64(len(test.content)), ""); err != nil {
+				t.Fatalf("Put() error = %v", err)
+			}
+			stored, err := ioutil.ReadFile(filepath.Join(store.Directory, test.key))
+			if err != nil || string(stored) != test.content {
+				t.Errorf("stored file = %q, %v, want %q", stored, err, test.content)
+			}
+
+			body, err := store.Get(ctx, test.key)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			content, err := ioutil.ReadAll(body)
+			_ = body.Close()
+			if err != nil || string(content) != test.content {
+				t.Errorf("Get() content = %q, %v, want %q", content, err, test.content)
+			}
+
+			signedURL, err := store.SignedURL(ctx, test.key, time.Hour)
+			if err != nil || signedURL != test.url {
+				t.Errorf("SignedURL() = %q, %v, want %q", signedURL, err, test.url)
+			}
+
+			if err = store.Delete(ctx, test.key); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if _, err = os.Stat(filepath.Join(store.Directory, test.key)); !os.IsNotExist(err) {
+				t.Errorf("file after Delete() stat error = %v, want not exist", err)
+			}
+			if _, err = store.Get(ctx, test.key); err != ErrNotFound {
+				t.Errorf("Get() after Delete() error = %v, want %v", err, ErrNotFound)
+			}
+			if err = store.Delete(ctx, test.key); err != ErrNotFound {
+				t.Errorf("Delete() after Delete() error = %v, want %v", err, ErrNotFound)
+			}
+		})
+	}
+}
+
+func TestLocalStoreOverwrite(t *testing.T) {
+	store := &LocalStore{Directory: t.TempDir()}
+	ctx := context.Background()
+	for _, content := range []string{"a longer first version", "second"} {
+		if err := store.Put(ctx, "photo.jpg", strings.NewReader(content), int64(len(content)), "image/jpeg"); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+	body, err := store.Get(ctx, "photo.jpg")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer body.Close()
+	if content, _ := ioutil.ReadAll(body); string(content) != "second" {
+		t.Errorf("Get() content = %q, want %q", content, "second")
+	}
+}
+
+func TestLocalStoreRejectsPaths(t *testing.T) {
+	directory := t.TempDir()
+	store := &LocalStore{Directory: filepath.Join(directory, "media")}
+	if err := os.Mkdir(store.Directory, 0777); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, key := range []string{"", "../escaped.jpg", "nested/photo.jpg", "/etc/passwd"} {
+		t.Run(key, func(t *testing.T) {
+			if err := store.Put(ctx, key, strings.NewReader("content"), 7, ""); err == nil {
+				t.Errorf("Put(%q) error = nil, want an invalid key error", key)
+			}
+			if _, err := store.Get(ctx, key); err == nil || err == ErrNotFound {
+				t.Errorf("Get(%q) error = %v, want an invalid key error", key, err)
+			}
+			if err := store.Delete(ctx, key); err == nil || err == ErrNotFound {
+				t.Errorf("Delete(%q) error = %v, want an invalid key error", key, err)
+			}
+			if _, err := store.SignedURL(ctx, key, time.Hour); err == nil {
+				t.Errorf("SignedURL(%q) error = nil, want an invalid key error", key)
+			}
+		})
+	}
+	if _, err := os.Stat(filepath.Join(directory, "escaped.jpg")); !os.IsNotExist(err) {
+		t.Errorf("file outside the directory stat error = %v, want not exist", err)
+	}
+}
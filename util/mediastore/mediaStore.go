@@ -0,0 +1,40 @@
+package mediastore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+var ErrNotFound = errors.New("MEDIA_NOT_FOUND")
+
+// MediaStore keeps uploaded media under flat keys, the same names that are saved on posts, messages and products.
+type MediaStore interface {
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+// NewFromEnv picks the backend from MEDIA_STORE; "s3" uses the S3_* variables, anything else stores files in directory.
+func NewFromEnv(directory string, baseURL string) MediaStore {
+	if os.Getenv("MEDIA_STORE") == "s3" {
+		region := os.Getenv("S3_REGION")
+		if region == "" {
+			region = "us-east-1"
+		}
+		return &S3Store{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    region,
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		}
+	}
+	if value, ok := os.LookupEnv("MEDIA_BASE_URL"); ok {
+		baseURL = value
+	}
+	return &LocalStore{Directory: directory, BaseURL: baseURL}
+}
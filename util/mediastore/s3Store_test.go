@@ -0,0 +1,279 @@
+package mediastore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testBucket    = "media"
+	testRegion    = "eu-central-1"
+	testAccessKey = "AKIDEXAMPLE"
+	testSecretKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+)
+
+// fakeS3 keeps objects in memory and only serves requests signed with the test credentials, checking the signature
+// with its own signature V4 implementation.
+type fakeS3 struct {
+	mu           sync.Mutex
+	objects      map[string][]byte
+	contentTypes map[string]string
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *S3Store) {
+	fake := &fakeS3{objects: make(map[string][]byte), contentTypes: make(map[string]string)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	store := &S3Store{Endpoint: server.URL + "/", Region: testRegion, Bucket: testBucket,
+		AccessKey: testAccessKey, SecretKey: testSecretKey, Client: server.Client()}
+	return fake, store
+}
+
+func (fake *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, "/"+testBucket+"/") {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+	if !fake.verify(r) {
+		http.Error(w, "SignatureDoesNotMatch", http.StatusForbidden)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, "/"+testBucket+"/")
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		content, err := ioutil.ReadAll(r.Body)
+		if err != nil || int64(len(content)) != r.ContentLength {
+			http.Error(w, "IncompleteBody", http.StatusBadRequest)
+			return
+		}
+		fake.objects[key] = content
+		fake.contentTypes[key] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		content, ok := fake.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(content)
+	case http.MethodDelete:
+		delete(fake.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// verify checks the Authorization header of signed requests and the query of presigned URLs.
+func (fake *fakeS3) verify(r *http.Request) bool {
+	query := r.URL.Query()
+	if signature := query.Get("X-Amz-Signature"); signature != "" {
+		if query.Get("X-Amz-Algorithm") != "AWS4-HMAC-SHA256" || query.Get("X-Amz-SignedHeaders") != "host" {
+			return false
+		}
+		date, err := time.Parse("20060102T150405Z", query.Get("X-Amz-Date"))
+		if err != nil || query.Get("X-Amz-Credential") != testAccessKey+"/"+testScope(date) {
+			return false
+		}
+		query.Del("X-Amz-Signature")
+		canonicalRequest := strings.Join([]string{r.Method, r.URL.EscapedPath(), testCanonicalQuery(query),
+			"host:" + r.Host + "\n", "host", unsignedPayload}, "\n")
+		return signature == testSignature(date, canonicalRequest)
+	}
+
+	authorization := r.Header.Get("Authorization")
+	prefix := "AWS4-HMAC-SHA256 Credential="
+	parts := strings.Split(strings.TrimPrefix(authorization, prefix), ", ")
+	if !strings.HasPrefix(authorization, prefix) || len(parts) != 3 ||
+		!strings.HasPrefix(parts[1], "SignedHeaders=") || !strings.HasPrefix(parts[2], "Signature=") {
+		return false
+	}
+	date, err := time.Parse("20060102T150405Z", r.Header.Get("X-Amz-Date"))
+	if err != nil || parts[0] != testAccessKey+"/"+testScope(date) {
+		return false
+	}
+	signedHeaders := strings.TrimPrefix(parts[1], "SignedHeaders=")
+	canonicalHeaders := ""
+	for _, name := range strings.Split(signedHeaders, ";") {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		canonicalHeaders += name + ":" + value + "\n"
+	}
+	for _, name := range []string{"host", "x-amz-date", "x-amz-content-sha256"} {
+		if !strings.Contains(";"+signedHeaders+";", ";"+name+";") {
+			return false
+		}
+	}
+	canonicalRequest := strings.Join([]string{r.Method, r.URL.EscapedPath(), testCanonicalQuery(query),
+		canonicalHeaders, signedHeaders, r.Header.Get("X-Amz-Content-Sha256")}, "\n")
+	return strings.TrimPrefix(parts[2], "Signature=") == testSignature(date, canonicalRequest)
+}
+
+func testScope(date time.Time) string {
+	return date.Format("20060102") + "/" + testRegion + "/s3/aws4_request"
+}
+
+func testCanonicalQuery(query url.Values) string {
+	parts := make([]string, 0)
+	for key, values := range query {
+		for _, value := range values {
+			parts = append(parts, testEscape(key)+"="+testEscape(value))
+		}
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "&")
+}
+
+func testEscape(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
+
+func testSignature(date time.Time, canonicalRequest string) string {
+	sum := func(key []byte, data string) []byte {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(data))
+		return mac.Sum(nil)
+	}
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + date.Format("20060102T150405Z") + "\n" + testScope(date) + "\n" +
+		hex.EncodeToString(hash[:])
+	key := sum([]byte("AWS4"+testSecretKey), date.Format("20060102"))
+	for _, part := range []string{testRegion, "s3", "aws4_request"} {
+		key = sum(key, part)
+	}
+	return hex.EncodeToString(sum(key, stringToSign))
+}
+
+func TestS3StoreRoundTrip(t *testing.T) {
+	fake, store := newFakeS3(t)
+	ctx := context.Background()
+	tests := []struct {
+		name        string
+		key         string
+		content     string
+		contentType string
+	}{
+		{"image", "5f2b0c1e-photo.jpg", "not really a jpeg", "image/jpeg"},
+		{"key that needs escaping", "summer trip (1)+ä.mp4", "not really a video", "video/mp4"},
+		{"empty file without content type", "empty.txt", "", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := store.Put(ctx, test.key, bytes.NewReader([]byte(test.content)), int64(len(test.content)), test.contentType)
+			if err != nil {
+				t.Fatalf("Put() error = %v", err)
+			}
+			if contentType := fake.contentTypes[test.key]; contentType != test.contentType {
+				t.Errorf("stored content type = %q, want %q", contentType, test.contentType)
+			}
+
+			body, err := store.Get(ctx, test.key)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			content, err := ioutil.ReadAll(body)
+			_ = body.Close()
+			if err != nil || string(content) != test.content {
+				t.Errorf("Get() content = %q, %v, want %q", content, err, test.content)
+			}
+
+			if err = store.Delete(ctx, test.key); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if _, err = store.Get(ctx, test.key); err != ErrNotFound {
+				t.Errorf("Get() after Delete() error = %v, want %v", err, ErrNotFound)
+			}
+		})
+	}
+}
+
+func TestS3StoreErrors(t *testing.T) {
+	_, store := newFakeS3(t)
+	ctx := context.Background()
+
+	if _, err := store.Get(ctx, "missing.jpg"); err != ErrNotFound {
+		t.Errorf("Get() of a missing key error = %v, want %v", err, ErrNotFound)
+	}
+
+	store.SecretKey = "wrong"
+	err := store.Put(ctx, "photo.jpg", strings.NewReader("content"), 7, "image/jpeg")
+	if err == nil || err == ErrNotFound || !strings.Contains(err.Error(), "403") ||
+		!strings.Contains(err.Error(), "SignatureDoesNotMatch") {
+		t.Errorf("Put() with a wrong secret error = %v, want the 403 response", err)
+	}
+	if err = store.Delete(ctx, "photo.jpg"); err == nil {
+		t.Errorf("Delete() with a wrong secret error = nil, want the 403 response")
+	}
+}
+
+func TestS3StoreSignedURL(t *testing.T) {
+	_, store := newFakeS3(t)
+	ctx := context.Background()
+	key := "summer trip (1).jpg"
+	if err := store.Put(ctx, key, strings.NewReader("content"), 7, "image/jpeg"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		expiry  time.Duration
+		expires string
+	}{
+		{"expiry", 15 * time.Minute, "900"},
+		{"no expiry is the longest", 0, "604800"},
+		{"longer than seven days", 30 * 24 * time.Hour, "604800"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			signedURL, err := store.SignedURL(ctx, key, test.expiry)
+			if err != nil {
+				t.Fatalf("SignedURL() error = %v", err)
+			}
+			u, err := url.Parse(signedURL)
+			if err != nil {
+				t.Fatalf("SignedURL() = %q is not a URL: %v", signedURL, err)
+			}
+			if expires := u.Query().Get("X-Amz-Expires"); expires != test.expires {
+				t.Errorf("X-Amz-Expires = %q, want %q", expires, test.expires)
+			}
+
+			resp, err := store.Client.Get(signedURL)
+			if err != nil {
+				t.Fatalf("GET signed URL error = %v", err)
+			}
+			content, _ := ioutil.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			if resp.StatusCode != http.StatusOK || string(content) != "content" {
+				t.Errorf("GET signed URL = %d %q, want %d %q", resp.StatusCode, content, http.StatusOK, "content")
+			}
+
+			query := u.Query()
+			query.Set("X-Amz-Expires", "1")
+			u.RawQuery = query.Encode()
+			resp, err = store.Client.Get(u.String())
+			if err != nil {
+				t.Fatalf("GET tampered URL error = %v", err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != http.StatusForbidden {
+				t.Errorf("GET tampered URL = %d, want %d", resp.StatusCode, http.StatusForbidden)
+			}
+		})
+	}
+}